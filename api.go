@@ -13,11 +13,11 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/DavidGamba/go-getoptions/internal/help"
 	"github.com/DavidGamba/go-getoptions/internal/option"
 	"github.com/DavidGamba/go-getoptions/internal/sliceiterator"
-	"github.com/DavidGamba/go-getoptions/text"
 )
 
 type programTree struct {
@@ -25,7 +25,9 @@ type programTree struct {
 	Name            string
 	Description     string
 	SynopsisArgs    []help.SynopsisArg
-	SynopsisArgsIdx int // idx for the GetRequiredArg helper
+	SynopsisArgsIdx int              // idx for the GetRequiredArg helper
+	argsSpec        *ArgsSpec        // number of positional args accepted, nil when not validated
+	positionalArgs  []*positionalArg // named positional args
 	ChildCommands   map[string]*programTree
 	ChildOptions    map[string]*option.Option
 	UnknownOptions  []*option.Option // Track unknown options in order in case they need to be passed to the remaining array.
@@ -37,11 +39,20 @@ type programTree struct {
 	mode            Mode
 	unknownMode     UnknownMode        // Unknown option mode
 	requireOrder    bool               // stop parsing args as soon as an unknown is found
+	didYouMean      bool               // suggest close names for unknown options and commands
 	skipOptionsCopy bool               // skips copying options from parent to child. Required when doing wrapper commands.
 	Suggestions     []string           // Suggestions used for argument completions
 	SuggestionFns   []ArgCompletionsFn // SuggestionsFns used for argument completions
+	// SuggestionContextFns used for argument completions that depend on the parsed options
+	SuggestionContextFns []ArgCompletionsContextFn
+	// SuggestionDescribedFns used for argument completions with descriptions
+	SuggestionDescribedFns []ArgCompletionsDescribedFn
 
-	mapKeysToLower bool // controls wether or not map keys are normalized to lowercase
+	mapKeysToLower bool   // controls wether or not map keys are normalized to lowercase
+	envSeparator   rune   // separator used to split env var values for slice and map options
+	envPrefix      string // prefix used to derive env var names for all options
+
+	configFiles []*configFile // configuration files read during Parse, only set in the root node
 
 	command
 }
@@ -117,7 +128,7 @@ func (n *programTree) AddChildOption(name string, opt *option.Option) {
 	}
 
 	switch opt.OptType {
-	case option.StringRepeatType, option.IntRepeatType, option.Float64RepeatType, option.StringMapType:
+	case option.StringRepeatType, option.IntRepeatType, option.Float64RepeatType, option.StringMapType, option.DurationRepeatType, option.ValueRepeatType:
 		err := opt.ValidateMinMaxArgs()
 		if err != nil {
 			panic(fmt.Sprintf("%s definition error: %s", name, err))
@@ -185,6 +196,10 @@ func parseCLIArgs(completionMode string, tree *programTree, args []string, mode
 
 	iterator := sliceiterator.New(&args)
 
+	// Option that takes the value being completed as a separate word
+	var valueOpt *option.Option
+	valueRequired := false
+
 ARGS_LOOP:
 	for iterator.Next() ||
 		(completionMode != "" && len(args) == 0) { // enter at least once if running in completion mode.
@@ -197,6 +212,23 @@ ARGS_LOOP:
 		if completionMode != "" && (iterator.IsLast() || len(args) == 0) {
 			completions := []string{}
 
+			// Values of an option given as a separate word, for example: `--opt <TAB>`
+			if valueOpt != nil {
+				for _, e := range optionValueSuggestions(completionMode, currentProgramNode, valueOpt, iterator.Value()) {
+					if strings.HasPrefix(e, iterator.Value()) {
+						completions = append(completions, e)
+					}
+				}
+				// When the value is required, commands and arguments are not valid completions.
+				if valueRequired {
+					sort.Strings(completions)
+					if len(completions) == 1 && completionMode == "bash" {
+						(completions)[0] = completions[0] + " "
+					}
+					return currentProgramNode, completions, nil
+				}
+			}
+
 			// Options
 			{
 				if strings.HasPrefix(iterator.Value(), "-") {
@@ -226,32 +258,15 @@ ARGS_LOOP:
 						// The entry is complete here and has suggestions
 						if strings.Contains(partialOption, "=") && strings.HasPrefix(partialOption, k) {
 							lastOpt = v
-							if lastOpt.SuggestedValues != nil && len(lastOpt.SuggestedValues) > 0 {
-								for _, e := range lastOpt.SuggestedValues {
-									c := fmt.Sprintf("--%s=%s", k, e)
-									if strings.HasPrefix(c, iterator.Value()) {
-										// NOTE: Bash completions have = as a special char and results should be trimmed form the = on.
-										if completionMode == "bash" {
-											tc := strings.SplitN(c, "=", 2)[1]
-											completions = append(completions, tc)
-										} else {
-											completions = append(completions, c)
-										}
-									}
-								}
-							}
-							// The entry is complete here and has a suggestion function
-							if strings.Contains(partialOption, "=") && lastOpt.SuggestedValuesFn != nil {
-								for _, e := range lastOpt.SuggestedValuesFn(completionMode, strings.SplitN(iterator.Value(), "=", 2)[1]) {
-									c := fmt.Sprintf("--%s=%s", k, e)
-									if strings.HasPrefix(c, iterator.Value()) {
-										// NOTE: Bash completions have = as a special char and results should be trimmed form the = on.
-										if completionMode == "bash" {
-											tc := strings.SplitN(c, "=", 2)[1]
-											completions = append(completions, tc)
-										} else {
-											completions = append(completions, c)
-										}
+							for _, e := range optionValueSuggestions(completionMode, currentProgramNode, lastOpt, strings.SplitN(iterator.Value(), "=", 2)[1]) {
+								c := fmt.Sprintf("--%s=%s", k, e)
+								if strings.HasPrefix(c, iterator.Value()) {
+									// NOTE: Bash completions have = as a special char and results should be trimmed form the = on.
+									if completionMode == "bash" {
+										tc := strings.SplitN(c, "=", 2)[1]
+										completions = append(completions, tc)
+									} else {
+										completions = append(completions, c)
 									}
 								}
 							}
@@ -268,7 +283,8 @@ ARGS_LOOP:
 							for _, e := range lastOpt.SuggestedValues {
 								completions = append(completions, completions[0]+e)
 							}
-						} else {
+						} else if completionMode == "bash" || completionMode == "zsh" {
+							// Fish and PowerShell show the option description instead.
 							valueStr := "<value>"
 							if lastOpt.HelpArgName != "" {
 								valueStr = "<" + lastOpt.HelpArgName + ">"
@@ -305,9 +321,34 @@ ARGS_LOOP:
 				for _, fn := range currentProgramNode.SuggestionFns {
 					completions = append(completions, fn(completionMode, currentProgramNode.ChildText, iterator.Value())...)
 				}
+				// Completions for the named argument at the cursor position.
+				position := len(currentProgramNode.ChildText)
+				if currentProgramNode.Parent == nil {
+					// The program name is part of the root text in completion mode.
+					position--
+				}
+				if arg := positionalArgAt(currentProgramNode, position); position >= 0 && arg != nil {
+					for _, fn := range arg.CompletionFns {
+						completions = append(completions, fn(completionMode, currentProgramNode.ChildText, iterator.Value())...)
+					}
+				}
+				for _, fn := range currentProgramNode.SuggestionContextFns {
+					completions = append(completions, fn(completionMode, parsedOptions(currentProgramNode.ChildOptions), currentProgramNode.ChildText, iterator.Value())...)
+				}
+				// Descriptions are carried after a tab and formatted per target by the caller.
+				// Bash can't display them.
+				for _, fn := range currentProgramNode.SuggestionDescribedFns {
+					for _, c := range fn(completionMode, currentProgramNode.ChildText, iterator.Value()) {
+						if c.Description == "" || completionMode == "bash" {
+							completions = append(completions, c.Value)
+						} else {
+							completions = append(completions, c.Value+"\t"+strings.Join(strings.Fields(c.Description), " "))
+						}
+					}
+				}
 			}
 
-			// Provide other kinds of completions, like file completions.
+			// File completions are provided through SuggestionFns, see opt.ArgCompleteFiles.
 
 			sort.Strings(completions)
 			// Add trailing space to force next completion, makes for nicer UI when there is a single result.
@@ -355,7 +396,7 @@ ARGS_LOOP:
 				optionMatches := getAliasNameFromPartialEntry(currentProgramNode, p.Option)
 				if len(optionMatches) > 1 {
 					sort.Strings(optionMatches)
-					err := fmt.Errorf(text.ErrorAmbiguousArgument, iterator.Value(), optionMatches)
+					err := &AmbiguousOptionError{Option: p.Option, Value: iterator.Value(), Candidates: optionMatches}
 					return currentProgramNode, []string{}, err
 				}
 
@@ -377,30 +418,41 @@ ARGS_LOOP:
 				// TODO: Check min, check max and keep ingesting until something starts with `-` or matches a command.
 
 				if cOpt, ok := currentProgramNode.ChildOptions[optionMatches[0]]; ok {
+					if cOpt.SourceKind == option.SourceEnv && cOpt.EnvReset != nil {
+						cOpt.EnvReset()
+					}
 					cOpt.Called = true
 					cOpt.UsedAlias = optionMatches[0]
+					cOpt.SetSource(option.SourceCLI, optionMatches[0])
 					cOpt.MapKeysToLower = tree.mapKeysToLower
 					err := cOpt.Save(p.Args...)
 					if err != nil {
-						return currentProgramNode, []string{}, err
+						return currentProgramNode, []string{}, optionError(err)
 					}
 					// TODO: Handle option having a minimum bigger than 1
 
 					// Validate minimum
 					i := len(p.Args) // if the value is part of the option, for example --opt=value then the minimum of 1 is already met.
 					for ; i < cOpt.MinArgs; i++ {
+						if completionMode != "" && iterator.IsNextLast() {
+							if value, _ := iterator.PeekNextValue(); !strings.HasPrefix(value, "-") {
+								valueOpt = cOpt
+								valueRequired = !cOpt.IsOptional
+								continue ARGS_LOOP
+							}
+						}
 						if !iterator.ExistsNext() && !cOpt.IsOptional {
-							err := fmt.Errorf(text.ErrorMissingArgument+"%w", cOpt.UsedAlias, ErrorParsing)
+							err := &MissingArgumentError{Option: cOpt.Name, UsedAlias: cOpt.UsedAlias}
 							return currentProgramNode, []string{}, err
 						}
 						iterator.Next()
 						if _, is := isOption(iterator.Value(), mode, false); is && !cOpt.IsOptional {
-							err := fmt.Errorf(text.ErrorArgumentWithDash+"%w", cOpt.UsedAlias, ErrorParsing)
+							err := &MissingArgumentError{Option: cOpt.Name, UsedAlias: cOpt.UsedAlias, Value: iterator.Value()}
 							return currentProgramNode, []string{}, err
 						}
 						err := cOpt.Save(iterator.Value())
 						if err != nil {
-							return currentProgramNode, []string{}, err
+							return currentProgramNode, []string{}, optionError(err)
 						}
 					}
 
@@ -414,6 +466,10 @@ ARGS_LOOP:
 						if _, is := isOption(value, mode, false); is {
 							break
 						}
+						if completionMode != "" && iterator.IsNextLast() {
+							valueOpt = cOpt
+							break
+						}
 
 						// Validate that value matches expected format
 						switch cOpt.OptType {
@@ -432,6 +488,12 @@ ARGS_LOOP:
 							if err != nil {
 								break MAX_LOOP
 							}
+						case option.DurationRepeatType:
+							// Next Value is not a duration entry, break the max feed.
+							_, err := time.ParseDuration(value)
+							if err != nil {
+								break MAX_LOOP
+							}
 						case option.StringMapType:
 							// Next Value is not a key=value entry, break the max feed.
 							if !strings.Contains(value, "=") {
@@ -442,7 +504,7 @@ ARGS_LOOP:
 						iterator.Next()
 						err := cOpt.Save(iterator.Value())
 						if err != nil {
-							return currentProgramNode, []string{}, err
+							return currentProgramNode, []string{}, optionError(err)
 						}
 					}
 				}
@@ -480,6 +542,35 @@ ARGS_LOOP:
 	return currentProgramNode, []string{}, nil
 }
 
+// optionValueSuggestions - Returns the suggested values for the option, including the ones from its completion functions.
+func optionValueSuggestions(completionMode string, n *programTree, opt *option.Option, partial string) []string {
+	values := []string{}
+	values = append(values, opt.SuggestedValues...)
+	if opt.SuggestedValuesFn != nil {
+		values = append(values, opt.SuggestedValuesFn(completionMode, partial)...)
+	}
+	if opt.SuggestedValuesContextFn != nil {
+		values = append(values, opt.SuggestedValuesContextFn(completionMode, n.ChildOptions, partial)...)
+	}
+	// The extra "dir/ " entry added by the file completions only applies when the dir is the only match.
+	matches := 0
+	for _, v := range values {
+		if strings.HasPrefix(v, partial) && !strings.HasSuffix(v, "/ ") {
+			matches++
+		}
+	}
+	if matches > 1 {
+		filtered := []string{}
+		for _, v := range values {
+			if !strings.HasSuffix(v, "/ ") {
+				filtered = append(filtered, v)
+			}
+		}
+		values = filtered
+	}
+	return values
+}
+
 func storeRemainingAsText(iterator *sliceiterator.Iterator, n *programTree) {
 	value := iterator.Value()
 	n.ChildText = append(n.ChildText, value)
@@ -11,6 +11,8 @@ package getoptions
 import (
 	"reflect"
 	"testing"
+
+	"github.com/DavidGamba/go-getoptions/internal/option"
 )
 
 func TestParseCLIArgs(t *testing.T) {
@@ -69,6 +71,7 @@ func TestParseCLIArgs(t *testing.T) {
 			}
 			opt.Called = true
 			opt.UsedAlias = "rootopt1"
+			opt.SetSource(option.SourceCLI, "rootopt1")
 			err := opt.Save("hello")
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
@@ -86,6 +89,7 @@ func TestParseCLIArgs(t *testing.T) {
 			}
 			opt.Called = true
 			opt.UsedAlias = "rootopt1"
+			opt.SetSource(option.SourceCLI, "rootopt1")
 			err := opt.Save("hello")
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
@@ -103,6 +107,7 @@ func TestParseCLIArgs(t *testing.T) {
 			}
 			opt.Called = true
 			opt.UsedAlias = "rootopt1"
+			opt.SetSource(option.SourceCLI, "rootopt1")
 			return n
 		}(), []string{}, ErrorParsing},
 
@@ -126,6 +131,7 @@ func TestParseCLIArgs(t *testing.T) {
 			}
 			opt.Called = true
 			opt.UsedAlias = "-"
+			opt.SetSource(option.SourceCLI, "-")
 			err = opt.Save("txt")
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
@@ -146,6 +152,7 @@ func TestParseCLIArgs(t *testing.T) {
 			}
 			opt.Called = true
 			opt.UsedAlias = "rootopt1"
+			opt.SetSource(option.SourceCLI, "rootopt1")
 			err = opt.Save("hello")
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
@@ -165,6 +172,7 @@ func TestParseCLIArgs(t *testing.T) {
 			}
 			opt.Called = true
 			opt.UsedAlias = "rootopt1"
+			opt.SetSource(option.SourceCLI, "rootopt1")
 			err = opt.Save("hello")
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
@@ -184,6 +192,7 @@ func TestParseCLIArgs(t *testing.T) {
 			}
 			opt.Called = true
 			opt.UsedAlias = "sub1cmd1opt1"
+			opt.SetSource(option.SourceCLI, "sub1cmd1opt1")
 			err = opt.Save("hello")
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
@@ -203,6 +212,7 @@ func TestParseCLIArgs(t *testing.T) {
 			}
 			opt.Called = true
 			opt.UsedAlias = "sub1cmd1opt1"
+			opt.SetSource(option.SourceCLI, "sub1cmd1opt1")
 			err = opt.Save("hello")
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
@@ -222,6 +232,7 @@ func TestParseCLIArgs(t *testing.T) {
 			}
 			opt.Called = true
 			opt.UsedAlias = "sub1cmd1opt1"
+			opt.SetSource(option.SourceCLI, "sub1cmd1opt1")
 			return n
 		}(), []string{}, ErrorParsing},
 
@@ -0,0 +1,306 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2025  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// ArgsSpec - Number of positional arguments accepted by a command, see `opt.Args`.
+type ArgsSpec struct {
+	Min int `json:"min"`
+	Max int `json:"max"` // -1 for no maximum
+}
+
+// ExactArgs - The command accepts exactly n positional arguments.
+func ExactArgs(n int) ArgsSpec {
+	return ArgsSpec{Min: n, Max: n}
+}
+
+// RangeArgs - The command accepts between min and max positional arguments, inclusive.
+func RangeArgs(min, max int) ArgsSpec {
+	return ArgsSpec{Min: min, Max: max}
+}
+
+// MinimumArgs - The command accepts at least n positional arguments.
+func MinimumArgs(n int) ArgsSpec {
+	return ArgsSpec{Min: n, Max: -1}
+}
+
+// MaximumArgs - The command accepts at most n positional arguments.
+func MaximumArgs(n int) ArgsSpec {
+	return ArgsSpec{Min: 0, Max: n}
+}
+
+// NoArgs - The command doesn't accept positional arguments.
+func NoArgs() ArgsSpec {
+	return ArgsSpec{Min: 0, Max: 0}
+}
+
+// positionalArg - Named positional argument defined with `opt.NamedArg` or the `opt.Positional*` methods.
+type positionalArg struct {
+	Name          string
+	Min           int // Minimum number of values
+	Max           int // Maximum number of values, -1 for no maximum
+	CompletionFns []ArgCompletionsFn
+	save          func(values ...string) error // nil when the argument is not bound to a variable
+}
+
+// addPositionalArg - Adds the positional argument to the command and its help.
+// Panics if max is smaller than min, if any argument follows a variadic one (no maximum)
+// or if a required argument follows one with optional values.
+func (gopt *GetOpt) addPositionalArg(arg *positionalArg, description string) *GetOpt {
+	if arg.Max >= 0 && arg.Max < arg.Min {
+		panic(fmt.Sprintf("Positional argument '%s' max %d is smaller than min %d", arg.Name, arg.Max, arg.Min))
+	}
+	if l := len(gopt.programTree.positionalArgs); l > 0 {
+		last := gopt.programTree.positionalArgs[l-1]
+		if last.Max < 0 {
+			panic(fmt.Sprintf("Positional argument '%s' defined after variadic argument '%s'", arg.Name, last.Name))
+		}
+		if last.Min < last.Max && arg.Min > 0 {
+			panic(fmt.Sprintf("Required positional argument '%s' defined after optional argument '%s'", arg.Name, last.Name))
+		}
+	}
+	gopt.programTree.positionalArgs = append(gopt.programTree.positionalArgs, arg)
+	gopt.HelpSynopsisArg(arg.Name, description)
+	return gopt
+}
+
+// positionalArgAt - Returns the positional argument that takes the value at the given position.
+func positionalArgAt(n *programTree, position int) *positionalArg {
+	for _, arg := range n.positionalArgs {
+		if arg.Max < 0 || position < arg.Max {
+			return arg
+		}
+		position -= arg.Max
+	}
+	return nil
+}
+
+// Args - Defines the number of positional arguments accepted by the command.
+// `opt.Parse` returns an `*getoptions.ArgsError` when the remaining arguments don't match it.
+//
+//	opt.Args(getoptions.ExactArgs(2))
+//	opt.Args(getoptions.RangeArgs(1, 3))
+//
+// The validation is skipped when the help is called.
+// When the unknown mode is `getoptions.Pass` or `getoptions.Warn`, unknown options are counted as arguments.
+func (gopt *GetOpt) Args(spec ArgsSpec) *GetOpt {
+	gopt.programTree.argsSpec = &spec
+	return gopt
+}
+
+// NamedArg - Defines a required named positional argument.
+//
+// Named arguments are shown in the help synopsis and ARGUMENTS section, like
+// the ones defined with `opt.HelpSynopsisArg`, and are completed with the given
+// completion functions when the cursor is on their position.
+//
+// Without a call to `opt.Args`, the command accepts exactly as many arguments
+// as named arguments were defined.
+// Missing arguments are reported by name, for example:
+//
+//	Missing <file>
+//
+// Use the `opt.Positional*` methods to bind the argument to a variable.
+func (gopt *GetOpt) NamedArg(name, description string, completionFns ...ArgCompletionsFn) *GetOpt {
+	return gopt.addPositionalArg(&positionalArg{Name: name, Min: 1, Max: 1, CompletionFns: completionFns}, description)
+}
+
+// nodeArgsSpec - Returns the spec set with `opt.Args` or the one derived from the named arguments.
+// Returns nil when the node doesn't restrict its positional arguments.
+func nodeArgsSpec(n *programTree) *ArgsSpec {
+	if n.argsSpec != nil || len(n.positionalArgs) == 0 {
+		return n.argsSpec
+	}
+	spec := &ArgsSpec{}
+	for _, arg := range n.positionalArgs {
+		spec.Min += arg.Min
+		if arg.Max < 0 || spec.Max < 0 {
+			spec.Max = -1
+		} else {
+			spec.Max += arg.Max
+		}
+	}
+	return spec
+}
+
+// validateArgs - Returns an error if the given positional arguments don't match the node spec.
+func validateArgs(n *programTree, args []string) error {
+	spec := nodeArgsSpec(n)
+	if spec == nil {
+		return nil
+	}
+	if len(args) < spec.Min {
+		e := &ArgsError{Command: getCurrentNodeName(n), Min: spec.Min, Max: spec.Max, Args: args}
+		position := 0
+		for _, arg := range n.positionalArgs {
+			position += arg.Min
+			if len(args) < position {
+				e.Name = arg.Name
+				break
+			}
+		}
+		return e
+	}
+	if spec.Max >= 0 && len(args) > spec.Max {
+		return &ArgsError{Command: getCurrentNodeName(n), Min: spec.Min, Max: spec.Max, Args: args}
+	}
+	return nil
+}
+
+// bindArgs - Saves the positional arguments into the variables bound with the `opt.Positional*` methods.
+func bindArgs(n *programTree, args []string) error {
+	for _, arg := range n.positionalArgs {
+		if len(args) == 0 {
+			break
+		}
+		end := len(args)
+		if arg.Max >= 0 && arg.Max < end {
+			end = arg.Max
+		}
+		if arg.save != nil {
+			err := arg.save(args[:end]...)
+			if err != nil {
+				return err
+			}
+		}
+		args = args[end:]
+	}
+	return nil
+}
+
+// positional - Returns a positional argument that saves its values with the given function.
+func positional(name string, min, max int, completionFns []ArgCompletionsFn, save func(values ...string) error) *positionalArg {
+	return &positionalArg{Name: name, Min: min, Max: max, CompletionFns: completionFns, save: save}
+}
+
+// singleValueMin - Returns the minimum number of values for a single value positional argument.
+func singleValueMin(isRequired bool) int {
+	if isRequired {
+		return 1
+	}
+	return 0
+}
+
+// argConversionError - Returns the error for a positional argument value that can't be converted.
+func argConversionError(name, value, typeName, format string, err error) error {
+	return &ConversionError{Argument: name, Value: value, Type: typeName, Err: err, msg: fmt.Sprintf(format, name, value)}
+}
+
+func (gopt *GetOpt) positionalString(p *string, name, description string, isRequired bool, completionFns []ArgCompletionsFn) *GetOpt {
+	return gopt.addPositionalArg(positional(name, singleValueMin(isRequired), 1, completionFns, func(values ...string) error {
+		*p = values[0]
+		return nil
+	}), description)
+}
+
+func (gopt *GetOpt) positionalInt(p *int, name, description string, isRequired bool, completionFns []ArgCompletionsFn) *GetOpt {
+	return gopt.addPositionalArg(positional(name, singleValueMin(isRequired), 1, completionFns, func(values ...string) error {
+		i, err := strconv.Atoi(values[0])
+		if err != nil {
+			return argConversionError(name, values[0], "int", text.ErrorConvertNamedArgumentToInt, err)
+		}
+		*p = i
+		return nil
+	}), description)
+}
+
+func (gopt *GetOpt) positionalFloat64(p *float64, name, description string, isRequired bool, completionFns []ArgCompletionsFn) *GetOpt {
+	return gopt.addPositionalArg(positional(name, singleValueMin(isRequired), 1, completionFns, func(values ...string) error {
+		f, err := strconv.ParseFloat(values[0], 64)
+		if err != nil {
+			return argConversionError(name, values[0], "float64", text.ErrorConvertNamedArgumentToFloat64, err)
+		}
+		*p = f
+		return nil
+	}), description)
+}
+
+// PositionalString - Binds a required positional argument to the given string.
+// The variable is set by `opt.Parse`.
+//
+// The argument is validated, shown in the help and completed like the ones defined with `opt.NamedArg`.
+//
+//	var src string
+//	opt.PositionalString(&src, "<src>", "Source file")
+func (gopt *GetOpt) PositionalString(p *string, name, description string, completionFns ...ArgCompletionsFn) *GetOpt {
+	return gopt.positionalString(p, name, description, true, completionFns)
+}
+
+// PositionalStringOptional - Binds an optional positional argument to the given string.
+// The variable keeps its value when the argument is not passed.
+//
+// Optional arguments can only be followed by other optional arguments.
+func (gopt *GetOpt) PositionalStringOptional(p *string, name, description string, completionFns ...ArgCompletionsFn) *GetOpt {
+	return gopt.positionalString(p, name, description, false, completionFns)
+}
+
+// PositionalInt - Binds a required positional argument to the given int.
+// Values that can't be converted make `opt.Parse` return a `*getoptions.ConversionError`.
+func (gopt *GetOpt) PositionalInt(p *int, name, description string, completionFns ...ArgCompletionsFn) *GetOpt {
+	return gopt.positionalInt(p, name, description, true, completionFns)
+}
+
+// PositionalIntOptional - Binds an optional positional argument to the given int.
+func (gopt *GetOpt) PositionalIntOptional(p *int, name, description string, completionFns ...ArgCompletionsFn) *GetOpt {
+	return gopt.positionalInt(p, name, description, false, completionFns)
+}
+
+// PositionalFloat64 - Binds a required positional argument to the given float64.
+// Values that can't be converted make `opt.Parse` return a `*getoptions.ConversionError`.
+func (gopt *GetOpt) PositionalFloat64(p *float64, name, description string, completionFns ...ArgCompletionsFn) *GetOpt {
+	return gopt.positionalFloat64(p, name, description, true, completionFns)
+}
+
+// PositionalFloat64Optional - Binds an optional positional argument to the given float64.
+func (gopt *GetOpt) PositionalFloat64Optional(p *float64, name, description string, completionFns ...ArgCompletionsFn) *GetOpt {
+	return gopt.positionalFloat64(p, name, description, false, completionFns)
+}
+
+// PositionalStringSlice - Binds the remaining positional arguments to the given slice.
+// It takes at least `min` and at most `max` arguments, -1 for no maximum.
+// A `min` of 0 makes the arguments optional.
+//
+// With a max of -1 it must be the last positional argument defined, with
+// optional values (min smaller than max) it can only be followed by optional arguments.
+//
+//	var files []string
+//	opt.PositionalStringSlice(&files, "<file>...", "Files to process", 1, -1)
+func (gopt *GetOpt) PositionalStringSlice(p *[]string, name, description string, min, max int, completionFns ...ArgCompletionsFn) *GetOpt {
+	return gopt.addPositionalArg(positional(name, min, max, completionFns, func(values ...string) error {
+		*p = append([]string{}, values...)
+		return nil
+	}), description)
+}
+
+// PositionalIntSlice - Binds the remaining positional arguments to the given int slice.
+// It takes at least `min` and at most `max` arguments, -1 for no maximum.
+// A `min` of 0 makes the arguments optional.
+//
+// With a max of -1 it must be the last positional argument defined, with
+// optional values (min smaller than max) it can only be followed by optional arguments.
+func (gopt *GetOpt) PositionalIntSlice(p *[]int, name, description string, min, max int, completionFns ...ArgCompletionsFn) *GetOpt {
+	return gopt.addPositionalArg(positional(name, min, max, completionFns, func(values ...string) error {
+		ii := []int{}
+		for _, v := range values {
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				return argConversionError(name, v, "int-slice", text.ErrorConvertNamedArgumentToInt, err)
+			}
+			ii = append(ii, i)
+		}
+		*p = ii
+		return nil
+	}), description)
+}
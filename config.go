@@ -0,0 +1,228 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2025  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/DavidGamba/go-getoptions/internal/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// ConfigUnmarshalFn - Function signature used to decode a configuration file.
+// It matches the signature of `json.Unmarshal` and of the Unmarshal functions
+// of the most common YAML and TOML libraries.
+type ConfigUnmarshalFn func(data []byte, v interface{}) error
+
+type configFile struct {
+	path      string
+	pathOpt   *option.Option // option holding the path, nil when the path is fixed
+	unmarshal ConfigUnmarshalFn
+}
+
+// ConfigFile - Reads option values from a configuration file during Parse.
+// Precedence higher to lower: CLI option, environment variable, configuration file, option default.
+//
+// The file is decoded with the given `unmarshal` function into a
+// `map[string]interface{}`, for example `json.Unmarshal`, `yaml.Unmarshal` or
+// `toml.Unmarshal`.
+// Top level keys map to option names or aliases.
+// Keys that match a command name hold a section with the options for that command:
+//
+//	{
+//	  "profile": "dev",
+//	  "log": {
+//	    "level": "debug"
+//	  }
+//	}
+//
+// Options set from a configuration file are marked as called and
+// `opt.CalledAs(name)` returns "config:<path>".
+//
+// A missing file is ignored.
+// When ConfigFile is called multiple times, files defined later take precedence.
+//
+// NOTE: Call on the top level GetOpt object.
+func (gopt *GetOpt) ConfigFile(path string, unmarshal ConfigUnmarshalFn) *GetOpt {
+	root := gopt.programTree
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	root.configFiles = append(root.configFiles, &configFile{path: path, unmarshal: unmarshal})
+	return gopt
+}
+
+// ConfigFileOption - define a `string` option that holds the path to a configuration file and its aliases.
+// The file is read during Parse with the same semantics as `opt.ConfigFile`.
+// If not called, the given default `def` path is used.
+//
+// When the option is passed explicitly, a missing file is reported as an error.
+func (gopt *GetOpt) ConfigFileOption(name, def string, unmarshal ConfigUnmarshalFn, fns ...ModifyFn) *string {
+	p := gopt.String(name, def, fns...)
+	root := gopt.programTree
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	root.configFiles = append(root.configFiles, &configFile{pathOpt: gopt.programTree.ChildOptions[name], unmarshal: unmarshal})
+	return p
+}
+
+// applyConfigFiles - Reads the configuration files defined in the root node
+// and sets the values of the options in the path from the root to the given
+// node that haven't been called.
+func applyConfigFiles(root, node *programTree) error {
+	if len(root.configFiles) == 0 {
+		return nil
+	}
+	path := map[*programTree]bool{}
+	for n := node; n != nil; n = n.Parent {
+		path[n] = true
+	}
+	// Options set by a file are marked as called so the first applied file wins.
+	for i := len(root.configFiles) - 1; i >= 0; i-- {
+		cf := root.configFiles[i]
+		filename := cf.path
+		if cf.pathOpt != nil {
+			filename = cf.pathOpt.Value().(string)
+		}
+		if filename == "" {
+			continue
+		}
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) && (cf.pathOpt == nil || !cf.pathOpt.Called) {
+				continue
+			}
+			return &wrappedError{msg: fmt.Sprintf(text.ErrorConfigFile, filename, err), err: err}
+		}
+		m := map[string]interface{}{}
+		err = cf.unmarshal(data, &m)
+		if err != nil {
+			return &wrappedError{msg: fmt.Sprintf(text.ErrorConfigFile, filename, err), err: err}
+		}
+		err = applyConfig(root, path, filename, m)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyConfig - Applies the command sections first so the most specific value wins.
+func applyConfig(n *programTree, path map[*programTree]bool, filename string, m map[string]interface{}) error {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		cmd, ok := n.ChildCommands[k]
+		if !ok {
+			continue
+		}
+		section, ok := configMap(m[k])
+		if !ok {
+			continue
+		}
+		if path[cmd] {
+			err := applyConfig(cmd, path, filename, section)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, k := range keys {
+		if _, ok := n.ChildCommands[k]; ok {
+			if _, ok := configMap(m[k]); ok {
+				continue
+			}
+		}
+		opt, ok := n.ChildOptions[k]
+		if !ok {
+			return fmt.Errorf("%w"+text.ErrorConfigUnknownKey, ErrorParsing, k, filename)
+		}
+		// Options set in the CLI or env vars take precedence, including other options of a mutually exclusive group.
+		if opt.Called || exclusiveMemberCalled(n, opt) {
+			continue
+		}
+		values := configValues(m[k])
+		opt.UsedAlias = k
+		opt.MapKeysToLower = n.mapKeysToLower
+		if opt.OptType == option.IncrementType && len(values) == 1 {
+			i, err := strconv.Atoi(values[0])
+			if err != nil {
+				err = &ConversionError{Option: opt.Name, UsedAlias: k, Value: values[0], Type: opt.OptType.String(), Err: err, msg: fmt.Sprintf(text.ErrorConvertToInt, k, values[0])}
+				return &wrappedError{msg: fmt.Sprintf(text.ErrorConfigFile, filename, err), err: err}
+			}
+			opt.SetInt(i)
+		} else {
+			err := opt.Save(values...)
+			if err != nil {
+				return &wrappedError{msg: fmt.Sprintf(text.ErrorConfigFile, filename, err), err: optionError(err)}
+			}
+		}
+		opt.SetCalled("config:" + filename)
+		opt.SetSource(option.SourceConfig, filename)
+	}
+	return nil
+}
+
+// configMap - Normalizes decoded maps, some YAML libraries use interface{} keys.
+func configMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		out := map[string]interface{}{}
+		for k, e := range m {
+			out[fmt.Sprintf("%v", k)] = e
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// configValues - Converts a decoded value into the string arguments used by option.Save.
+func configValues(v interface{}) []string {
+	if m, ok := configMap(v); ok {
+		keys := []string{}
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := []string{}
+		for _, k := range keys {
+			values = append(values, k+"="+configValue(m[k]))
+		}
+		return values
+	}
+	if list, ok := v.([]interface{}); ok {
+		values := []string{}
+		for _, e := range list {
+			values = append(values, configValue(e))
+		}
+		return values
+	}
+	return []string{configValue(v)}
+}
+
+func configValue(v interface{}) string {
+	switch e := v.(type) {
+	case string:
+		return e
+	case float64:
+		return strconv.FormatFloat(e, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", e)
+	}
+}
@@ -0,0 +1,122 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2025  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// didYouMeanDistance - Maximum edit distance for a name to be suggested.
+const didYouMeanDistance = 2
+
+// SetDidYouMean - Enables or disables "did you mean" suggestions for unknown options and mistyped commands.
+// Disabled by default.
+//
+// When enabled, unknown option errors and warnings suggest the closest option names:
+//
+//	Unknown option 'verbos', did you mean '--verbose'?
+//
+// And when a command has child commands, a first argument that is close to a
+// command name makes `opt.Parse` return an `*getoptions.UnknownCommandError`:
+//
+//	Unknown command 'lgo', did you mean 'log'?
+//
+// Child commands inherit the setting from their parent when they are created.
+func (gopt *GetOpt) SetDidYouMean(enabled bool) *GetOpt {
+	gopt.programTree.didYouMean = enabled
+	return gopt
+}
+
+// closestNames - Returns the sorted candidates within the edit distance of the given name.
+// Candidates that are not longer than the distance are only suggested on a prefix match.
+func closestNames(name string, candidates []string) []string {
+	names := []string{}
+	for _, c := range candidates {
+		if c == name {
+			continue
+		}
+		d := levenshtein(name, c)
+		if d <= didYouMeanDistance && d < len(c) && d < len(name) ||
+			len(name) > 1 && strings.HasPrefix(c, name) {
+			names = append(names, c)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// levenshtein - Returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// didYouMean - Returns the suggestion message for the given names.
+// Empty if there are no names.
+func didYouMean(names []string, format func(string) string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	quoted := []string{}
+	for _, name := range names {
+		quoted = append(quoted, "'"+format(name)+"'")
+	}
+	return fmt.Sprintf(text.MessageDidYouMean, strings.Join(quoted, ", "))
+}
+
+// optionDashes - Returns the option name with the leading dashes used in the CLI.
+func optionDashes(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
+
+// nodeOptionNames - Returns the option names and aliases of the node.
+func nodeOptionNames(n *programTree) []string {
+	names := []string{}
+	for name := range n.ChildOptions {
+		// skip the lonesome dash
+		if name == "-" {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
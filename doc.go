@@ -0,0 +1,207 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2025  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/DavidGamba/go-getoptions/internal/help"
+	"github.com/DavidGamba/go-getoptions/internal/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// DocFormat - Indicates the markup used by `opt.ReferenceDoc`.
+type DocFormat int
+
+// Reference documentation formats
+const (
+	DocMarkdown DocFormat = iota
+	DocAsciiDoc
+)
+
+// docMaxHeadingLevel - Deepest heading supported by both Markdown and AsciiDoc.
+const docMaxHeadingLevel = 6
+
+// docMarkup - Format specific markup.
+type docMarkup struct {
+	heading   func(level int, title string) string
+	codeBlock func(code string) string
+	code      func(s string) string
+	table     func(header []string, rows [][]string) string
+}
+
+var docMarkups = map[DocFormat]docMarkup{
+	DocMarkdown: {
+		heading: func(level int, title string) string {
+			return fmt.Sprintf("%s %s\n\n", strings.Repeat("#", level), title)
+		},
+		codeBlock: func(code string) string {
+			return fmt.Sprintf("```\n%s\n```\n\n", code)
+		},
+		code: func(s string) string {
+			return "`" + s + "`"
+		},
+		table: func(header []string, rows [][]string) string {
+			escape := strings.NewReplacer("|", `\|`, "\n", "<br>")
+			line := func(cells []string) string {
+				escaped := []string{}
+				for _, cell := range cells {
+					escaped = append(escaped, escape.Replace(cell))
+				}
+				return "| " + strings.Join(escaped, " | ") + " |\n"
+			}
+			out := line(header)
+			out += strings.Repeat("| --- ", len(header)) + "|\n"
+			for _, row := range rows {
+				out += line(row)
+			}
+			return out + "\n"
+		},
+	},
+	DocAsciiDoc: {
+		heading: func(level int, title string) string {
+			return fmt.Sprintf("%s %s\n\n", strings.Repeat("=", level), title)
+		},
+		codeBlock: func(code string) string {
+			return fmt.Sprintf("----\n%s\n----\n\n", code)
+		},
+		code: func(s string) string {
+			return "`+" + s + "+`"
+		},
+		table: func(header []string, rows [][]string) string {
+			escape := strings.NewReplacer("|", `\|`, "\n", " +\n")
+			out := "[options=\"header\"]\n|===\n"
+			line := func(cells []string) string {
+				txt := ""
+				for _, cell := range cells {
+					txt += "|" + escape.Replace(cell) + "\n"
+				}
+				return txt
+			}
+			out += strings.TrimSuffix(strings.ReplaceAll(line(header), "\n", " "), " ") + "\n\n"
+			for _, row := range rows {
+				out += line(row) + "\n"
+			}
+			return out + "|===\n\n"
+		},
+	},
+}
+
+// ReferenceDoc - Writes a reference document for the current command and all
+// its child commands in Markdown or AsciiDoc.
+//
+// Each command gets a section with its description, synopsis, arguments,
+// child commands and a table with the options, defaults, valid values and
+// environment variables.
+// Child command sections are nested one level below the sections of their
+// parent, up to the deepest heading level supported by the format.
+// The help command is not documented.
+func (gopt *GetOpt) ReferenceDoc(w io.Writer, format DocFormat) error {
+	markup, ok := docMarkups[format]
+	if !ok {
+		return fmt.Errorf(text.ErrorUnknownDocFormat, format)
+	}
+	_, err := io.WriteString(w, referenceDoc(gopt.programTree, markup, 1))
+	return err
+}
+
+func referenceDoc(n *programTree, markup docMarkup, level int) string {
+	options := nodeOptions(n)
+	commands := nodeCommands(n)
+	commandNames := []string{}
+	for _, command := range commands {
+		commandNames = append(commandNames, command.Name)
+	}
+
+	heading := func(level int, title string) string {
+		if level > docMaxHeadingLevel {
+			level = docMaxHeadingLevel
+		}
+		return markup.heading(level, title)
+	}
+
+	out := heading(level, getCurrentNodeName(n))
+	if n.Description != "" {
+		out += n.Description + "\n\n"
+	}
+
+	// Reuse the help synopsis, dropping its header and indentation.
+	synopsis := strings.Split(strings.TrimSuffix(help.Synopsis("", getCurrentNodeName(n), n.SynopsisArgs, options, commandNames), "\n"), "\n")[1:]
+	for i := range synopsis {
+		synopsis[i] = strings.TrimPrefix(synopsis[i], strings.Repeat(" ", help.Indentation))
+	}
+	out += heading(level+1, text.HelpSynopsisHeader)
+	out += markup.codeBlock(strings.Join(synopsis, "\n"))
+
+	if len(commands) > 0 {
+		rows := [][]string{}
+		for _, command := range commands {
+			rows = append(rows, []string{markup.code(command.Name), command.Description})
+		}
+		out += heading(level+1, text.HelpCommandsHeader)
+		out += markup.table([]string{text.DocCommandColumn, text.DocDescriptionColumn}, rows)
+	}
+
+	args := [][]string{}
+	for _, arg := range n.SynopsisArgs {
+		if arg.Arg != "" && arg.Description != "" {
+			args = append(args, []string{markup.code(arg.Arg), arg.Description})
+		}
+	}
+	if len(args) > 0 {
+		out += heading(level+1, text.HelpArgumentsHeader)
+		out += markup.table([]string{text.DocArgumentColumn, text.DocDescriptionColumn}, args)
+	}
+
+	normalOptions := []*option.Option{}
+	requiredOptions := []*option.Option{}
+	for _, opt := range options {
+		if opt.IsRequired {
+			requiredOptions = append(requiredOptions, opt)
+		} else {
+			normalOptions = append(normalOptions, opt)
+		}
+	}
+	option.Sort(normalOptions)
+	option.Sort(requiredOptions)
+	optionRows := func(options []*option.Option) [][]string {
+		rows := [][]string{}
+		for _, opt := range options {
+			validValues := []string{}
+			for _, v := range opt.ValidValues {
+				validValues = append(validValues, markup.code(v))
+			}
+			row := []string{markup.code(opt.HelpSynopsis), opt.Description, "", strings.Join(validValues, ", "), ""}
+			if !opt.IsRequired {
+				row[2] = markup.code(opt.DefaultStr)
+			}
+			if opt.EnvVar != "" {
+				row[4] = markup.code(opt.EnvVar)
+			}
+			rows = append(rows, row)
+		}
+		return rows
+	}
+	header := []string{text.DocOptionColumn, text.DocDescriptionColumn, text.DocDefaultColumn, text.DocValidValuesColumn, text.DocEnvironmentColumn}
+	if len(requiredOptions) > 0 {
+		out += heading(level+1, text.HelpRequiredOptionsHeader)
+		out += markup.table(header, optionRows(requiredOptions))
+	}
+	if len(normalOptions) > 0 {
+		out += heading(level+1, text.HelpOptionsHeader)
+		out += markup.table(header, optionRows(normalOptions))
+	}
+
+	for _, command := range commands {
+		out += referenceDoc(command, markup, level+2)
+	}
+	return out
+}
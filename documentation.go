@@ -35,7 +35,7 @@ See https://github.com/DavidGamba/go-getoptions for extra documentation details.
 
 • Simple synopsis and option list automated help.
 
-• Boolean, String, Int and Float64 type options.
+• Boolean, String, Int, Float64 and Duration type options.
 
 • Options with Array arguments.
 The same option can be used multiple times with different arguments.
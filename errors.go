@@ -11,13 +11,207 @@ package getoptions
 import (
 	"errors"
 	"fmt"
+
+	"github.com/DavidGamba/go-getoptions/internal/option"
+	"github.com/DavidGamba/go-getoptions/text"
 )
 
 // ErrorHelpCalled - Indicates the help has been handled.
 var ErrorHelpCalled = fmt.Errorf("help called")
 
 // ErrorParsing - Indicates that there was an error with cli args parsing
+//
+// All the parse error types below match ErrorParsing with `errors.Is`.
 var ErrorParsing = errors.New("")
 
 // ErrorNotFound - Generic not found error
 var ErrorNotFound = fmt.Errorf("not found")
+
+// UnknownOptionError - An option that wasn't defined was passed on the command line.
+// Only returned when the unknown mode is `getoptions.Fail`.
+//
+// Suggestions are only set when enabled with `opt.SetDidYouMean`.
+type UnknownOptionError struct {
+	Option      string   // Option as passed on the command line without leading dashes
+	UsedAlias   string   // Alias used on the command line, unknown options have no other aliases so it matches Option
+	Value       string   // Argument as passed on the command line, for example: "--unknown=value"
+	Suggestions []string // Closest option names and aliases without leading dashes
+}
+
+func (e *UnknownOptionError) Error() string {
+	return fmt.Sprintf(text.MessageOnUnknown, e.Option) + didYouMean(e.Suggestions, optionDashes)
+}
+
+func (e *UnknownOptionError) Is(target error) bool {
+	return target == ErrorParsing
+}
+
+// UnknownCommandError - The first argument is close to the name of a command but doesn't match it.
+// Only returned when enabled with `opt.SetDidYouMean`.
+type UnknownCommandError struct {
+	Command     string   // Argument as passed on the command line
+	Suggestions []string // Closest command names
+}
+
+func (e *UnknownCommandError) Error() string {
+	return fmt.Sprintf(text.ErrorUnknownCommand, e.Command) + didYouMean(e.Suggestions, func(s string) string { return s })
+}
+
+func (e *UnknownCommandError) Is(target error) bool {
+	return target == ErrorParsing
+}
+
+// ArgsError - The positional arguments don't match the spec defined with `opt.Args` or `opt.NamedArg`.
+type ArgsError struct {
+	Command string   // Command path, for example: "mytool deploy"
+	Name    string   // Name of the first missing argument, if it was defined with `opt.NamedArg` or `opt.Positional*`
+	Min     int      // Minimum number of arguments
+	Max     int      // Maximum number of arguments, -1 for no maximum
+	Args    []string // Arguments received
+}
+
+func (e *ArgsError) Error() string {
+	if len(e.Args) < e.Min && e.Name != "" {
+		return fmt.Sprintf(text.ErrorMissingRequiredNamedArgument, e.Name)
+	}
+	if e.Min == e.Max {
+		return fmt.Sprintf(text.ErrorExactArguments, e.Min, len(e.Args))
+	}
+	if len(e.Args) < e.Min {
+		return fmt.Sprintf(text.ErrorNotEnoughArguments, e.Min, len(e.Args))
+	}
+	return fmt.Sprintf(text.ErrorTooManyArguments, e.Max, len(e.Args))
+}
+
+func (e *ArgsError) Is(target error) bool {
+	return target == ErrorParsing
+}
+
+// AmbiguousOptionError - A partial option matches more than one option.
+type AmbiguousOptionError struct {
+	Option     string   // Partial option without leading dashes
+	Value      string   // Argument as passed on the command line
+	Candidates []string // Sorted names and aliases that match the partial option
+}
+
+func (e *AmbiguousOptionError) Error() string {
+	return fmt.Sprintf(text.ErrorAmbiguousArgument, e.Value, e.Candidates)
+}
+
+func (e *AmbiguousOptionError) Is(target error) bool {
+	return target == ErrorParsing
+}
+
+// MissingArgumentError - An option that requires an argument was passed without one.
+//
+// When the next argument looks like an option, it is the Value.
+type MissingArgumentError struct {
+	Option    string // Option name
+	UsedAlias string // Alias used on the command line
+	Value     string // Argument that starts with a dash, if any
+}
+
+func (e *MissingArgumentError) Error() string {
+	if e.Value != "" {
+		return fmt.Sprintf(text.ErrorArgumentWithDash, e.UsedAlias)
+	}
+	return fmt.Sprintf(text.ErrorMissingArgument, e.UsedAlias)
+}
+
+func (e *MissingArgumentError) Is(target error) bool {
+	return target == ErrorParsing
+}
+
+// ConversionError - An argument can't be converted to the option type.
+//
+// Err holds the underlying error, for example the error returned by the
+// `Set` method of a user defined `getoptions.Value`.
+type ConversionError struct {
+	Option    string // Option name, empty for positional arguments
+	Argument  string // Positional argument name, when bound with the `opt.Positional*` methods
+	UsedAlias string // Alias used on the command line, the config file key or the option name for env vars
+	Value     string // Argument that failed the conversion
+	Type      string // Option type as reported in the Schema, for example: "int" or "int-slice"
+	Err       error
+	msg       string
+}
+
+func (e *ConversionError) Error() string {
+	return e.msg
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ConversionError) Is(target error) bool {
+	return target == ErrorParsing
+}
+
+// InvalidValueError - An argument is not one of the option's valid values.
+type InvalidValueError struct {
+	Option      string   // Option name
+	UsedAlias   string   // Alias used on the command line
+	Value       string   // Argument that isn't valid
+	ValidValues []string // Values defined with `opt.ValidValues`
+	msg         string
+}
+
+func (e *InvalidValueError) Error() string {
+	return e.msg
+}
+
+func (e *InvalidValueError) Is(target error) bool {
+	return target == ErrorParsing
+}
+
+// MutuallyExclusiveError - More than one option of a group defined with `opt.MutuallyExclusive` was passed on the command line.
+//
+// UsedAliases holds the env var name for values set from environment
+// variables that can't be reset, for example user defined values without a
+// `Snapshot() func()` method.
+type MutuallyExclusiveError struct {
+	Options     []string // Names of the options passed
+	UsedAliases []string // Aliases used to call the options, without leading dashes, or env var names
+	Group       []string // Names of all the options in the group
+	msg         string
+}
+
+func (e *MutuallyExclusiveError) Error() string {
+	return e.msg
+}
+
+func (e *MutuallyExclusiveError) Is(target error) bool {
+	return target == ErrorParsing
+}
+
+// optionError - Converts the errors returned when saving an option into the exported error types.
+func optionError(err error) error {
+	var e *option.Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	if e.Kind == option.InvalidValueError {
+		return &InvalidValueError{Option: e.Name, UsedAlias: e.UsedAlias, Value: e.Value, ValidValues: e.ValidValues, msg: e.Error()}
+	}
+	return &ConversionError{Option: e.Name, UsedAlias: e.UsedAlias, Value: e.Value, Type: e.OptType.String(), Err: e.Err, msg: e.Error()}
+}
+
+// wrappedError - Error with its own message that matches the wrapped error
+// with `errors.Is` and `errors.As`.
+type wrappedError struct {
+	msg string
+	err error
+}
+
+func (e *wrappedError) Error() string {
+	return e.msg
+}
+
+func (e *wrappedError) Unwrap() error {
+	return e.err
+}
+
+func (e *wrappedError) Is(target error) bool {
+	return target == ErrorParsing
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/DavidGamba/go-getoptions"
+)
+
+func main() {
+	os.Exit(program(os.Args))
+}
+
+func program(args []string) int {
+	ctx, cancel, done := getoptions.InterruptContext()
+	defer func() { cancel(); <-done }()
+
+	opt := getoptions.New()
+	opt.Self("schemadiff", "Reports breaking changes between two CLI schemas generated with opt.Schema()")
+	opt.HelpSynopsisArg("<old.json>", "Schema of the previous release.")
+	opt.HelpSynopsisArg("<new.json>", "Schema of the new release.")
+	opt.SetCommandFn(Run)
+	opt.HelpCommand("help", opt.Alias("?"))
+	remaining, err := opt.Parse(args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+		return 1
+	}
+
+	err = opt.Dispatch(ctx, remaining)
+	if err != nil {
+		if errors.Is(err, getoptions.ErrorHelpCalled) {
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+		if errors.Is(err, getoptions.ErrorParsing) {
+			fmt.Fprintf(os.Stderr, "\n"+opt.Help())
+		}
+		return 1
+	}
+	return 0
+}
+
+func Run(ctx context.Context, opt *getoptions.GetOpt, args []string) error {
+	oldFile, args, err := opt.GetRequiredArg(args)
+	if err != nil {
+		return err
+	}
+	newFile, _, err := opt.GetRequiredArg(args)
+	if err != nil {
+		return err
+	}
+	oldSchema, err := readSchema(oldFile)
+	if err != nil {
+		return err
+	}
+	newSchema, err := readSchema(newFile)
+	if err != nil {
+		return err
+	}
+
+	changes := getoptions.CompareSchemas(oldSchema, newSchema)
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if len(changes) > 0 {
+		return fmt.Errorf("found %d breaking changes", len(changes))
+	}
+	return nil
+}
+
+func readSchema(filename string) (*getoptions.Schema, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	s := &getoptions.Schema{}
+	err = json.Unmarshal(data, s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode '%s': %w", filename, err)
+	}
+	return s, nil
+}
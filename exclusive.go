@@ -0,0 +1,127 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2025  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DavidGamba/go-getoptions/internal/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// MutuallyExclusive - Defines a group of options where only one of them can be passed on the command line.
+// The options must be defined before the call, by name or alias.
+//
+//	opt.Bool("json", false)
+//	opt.Bool("yaml", false)
+//	opt.Bool("table", false)
+//	opt.MutuallyExclusive("json", "yaml", "table")
+//
+// `opt.Parse` returns a `*getoptions.MutuallyExclusiveError` when more than one of them is called.
+// When one of them is passed on the command line, values of the other ones set
+// from environment variables or configuration files are ignored.
+// The group is shown in the help synopsis as `(--json|--yaml|--table)`.
+//
+// Panics if less than two options are given, if an option is not defined or if it is already part of a group.
+func (gopt *GetOpt) MutuallyExclusive(names ...string) *GetOpt {
+	if len(names) < 2 {
+		panic(fmt.Sprintf("Mutually exclusive group %q needs at least two options", names))
+	}
+	options := []*option.Option{}
+	group := []string{}
+	for _, name := range names {
+		opt, ok := gopt.programTree.ChildOptions[name]
+		if !ok {
+			panic(fmt.Sprintf("Mutually exclusive option '%s' is not defined", name))
+		}
+		if opt.ExclusiveGroup != nil {
+			panic(fmt.Sprintf("Option '%s' is already part of the mutually exclusive group %q", name, opt.ExclusiveGroup))
+		}
+		options = append(options, opt)
+		group = append(group, opt.Name)
+	}
+	for _, opt := range options {
+		opt.ExclusiveGroup = group
+	}
+	return gopt
+}
+
+// validateExclusive - Returns an error if more than one option of a mutually exclusive group was passed on the command line.
+// When one option of a group is passed on the command line, env var values of the other options are reset since the CLI takes precedence.
+func validateExclusive(n *programTree) error {
+	options := nodeOptions(n)
+	sort.Slice(options, func(i, j int) bool { return options[i].Name < options[j].Name })
+	seen := map[string]bool{}
+	for _, opt := range options {
+		if opt.ExclusiveGroup == nil || seen[opt.ExclusiveGroup[0]] {
+			continue
+		}
+		seen[opt.ExclusiveGroup[0]] = true
+		cli := []*option.Option{}
+		env := []*option.Option{}
+		for _, name := range opt.ExclusiveGroup {
+			member, ok := n.ChildOptions[name]
+			if !ok || !member.Called {
+				continue
+			}
+			switch member.SourceKind {
+			case option.SourceCLI:
+				cli = append(cli, member)
+			case option.SourceEnv:
+				env = append(env, member)
+			}
+		}
+		if len(cli) == 0 {
+			continue
+		}
+		conflicts := cli
+		for _, member := range env {
+			if member.EnvReset == nil {
+				conflicts = append(conflicts, member)
+				continue
+			}
+			member.EnvReset()
+			member.Called = false
+			member.UsedAlias = ""
+			member.SetSource(option.SourceDefault, "")
+		}
+		if len(conflicts) > 1 {
+			return mutuallyExclusiveError(opt.ExclusiveGroup, conflicts)
+		}
+	}
+	return nil
+}
+
+// exclusiveMemberCalled - Indicates if another option of the option's mutually exclusive group was called.
+func exclusiveMemberCalled(n *programTree, opt *option.Option) bool {
+	for _, name := range opt.ExclusiveGroup {
+		if member, ok := n.ChildOptions[name]; ok && member != opt && member.Called {
+			return true
+		}
+	}
+	return false
+}
+
+func mutuallyExclusiveError(group []string, conflicts []*option.Option) error {
+	e := &MutuallyExclusiveError{Group: group}
+	quoted := []string{}
+	for _, member := range conflicts {
+		e.Options = append(e.Options, member.Name)
+		e.UsedAliases = append(e.UsedAliases, member.UsedAlias)
+		if member.SourceKind == option.SourceCLI {
+			quoted = append(quoted, "'"+optionDashes(member.UsedAlias)+"'")
+		} else {
+			quoted = append(quoted, "'"+member.UsedAlias+"'")
+		}
+	}
+	e.msg = fmt.Sprintf(text.ErrorMutuallyExclusive, strings.Join(quoted, ", "))
+	return e
+}
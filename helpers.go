@@ -23,7 +23,7 @@ func (gopt *GetOpt) GetRequiredArg(args []string, sections ...HelpSection) (stri
 	if len(args) < 1 {
 		if len(gopt.programTree.SynopsisArgs) > gopt.programTree.SynopsisArgsIdx {
 			argName := gopt.programTree.SynopsisArgs[gopt.programTree.SynopsisArgsIdx].Arg
-			fmt.Fprintf(Writer, text.ErrorMissingRequiredNamedArgument+"\n", argName)
+			fmt.Fprintf(Writer, "ERROR: "+text.ErrorMissingRequiredNamedArgument+"\n", argName)
 		} else {
 			fmt.Fprintf(Writer, "%s\n", text.ErrorMissingRequiredArgument)
 		}
@@ -47,7 +47,7 @@ func (gopt *GetOpt) GetRequiredArgInt(args []string, sections ...HelpSection) (i
 	}
 	i, err := strconv.Atoi(arg)
 	if err != nil {
-		return 0, args, fmt.Errorf(text.ErrorConvertArgumentToInt, arg)
+		return 0, args, &ConversionError{Value: arg, Type: "int", Err: err, msg: fmt.Sprintf(text.ErrorConvertArgumentToInt, arg)}
 	}
 	return i, args, nil
 }
@@ -60,7 +60,7 @@ func (gopt *GetOpt) GetRequiredArgFloat64(args []string, sections ...HelpSection
 	}
 	f, err := strconv.ParseFloat(arg, 64)
 	if err != nil {
-		return 0, args, fmt.Errorf(text.ErrorConvertArgumentToFloat64, arg)
+		return 0, args, &ConversionError{Value: arg, Type: "float64", Err: err, msg: fmt.Sprintf(text.ErrorConvertArgumentToFloat64, arg)}
 	}
 	return f, args, nil
 }
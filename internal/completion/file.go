@@ -158,3 +158,43 @@ func listDir(dirname string, prefix string) ([]string, error) {
 	Debug.Printf("listDir - dirname %s, prefix %s > files %v\n", dirname, prefix, filenames)
 	return filenames, err
 }
+
+// Files - Given a prefix returns the list of files and dirs that match it relative to the current dir.
+// When globs are given, only files whose base name matches one of the globs are returned.
+// When dirsOnly is set, only dirs are returned.
+// Dirs are always returned so they can be navigated.
+func Files(prefix string, globs []string, dirsOnly bool) []string {
+	dirname := "."
+	if filepath.IsAbs(prefix) {
+		dirname = string(os.PathSeparator)
+	}
+	list, _ := listDir(dirname, prefix)
+	filenames := []string{}
+	for _, name := range list {
+		if strings.HasSuffix(name, " ") {
+			// listDir single dir entry, added back after filtering
+			continue
+		}
+		if strings.HasSuffix(name, "/") {
+			filenames = append(filenames, name)
+			continue
+		}
+		if dirsOnly {
+			continue
+		}
+		if len(globs) == 0 {
+			filenames = append(filenames, name)
+			continue
+		}
+		for _, glob := range globs {
+			if ok, _ := filepath.Match(glob, filepath.Base(name)); ok {
+				filenames = append(filenames, name)
+				break
+			}
+		}
+	}
+	if len(filenames) == 1 && strings.HasSuffix(filenames[0], "/") {
+		filenames = append(filenames, filenames[0]+" ")
+	}
+	return filenames
+}
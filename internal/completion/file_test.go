@@ -53,6 +53,31 @@ func TestListDir(t *testing.T) {
 	}
 }
 
+func TestFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		globs    []string
+		dirsOnly bool
+		list     []string
+	}{
+		{"all", "test/test_tree/", nil, false, []string{"test/test_tree/aFile1", "test/test_tree/aFile2", "test/test_tree/.aFile2", "test/test_tree/..aFile2", "test/test_tree/...aFile2", "test/test_tree/bDir1/", "test/test_tree/bDir2/", "test/test_tree/cFile1", "test/test_tree/cFile2"}},
+		{"globs", "test/test_tree/", []string{"a*1", "c*2"}, false, []string{"test/test_tree/aFile1", "test/test_tree/bDir1/", "test/test_tree/bDir2/", "test/test_tree/cFile2"}},
+		{"dirs", "test/test_tree/", nil, true, []string{"test/test_tree/bDir1/", "test/test_tree/bDir2/"}},
+		{"dir prefix with globs", "test/test_tree/bDir", []string{"*.go"}, false, []string{"test/test_tree/bDir1/", "test/test_tree/bDir2/"}},
+		{"single dir", "test/test", nil, true, []string{"test/test_tree/", "test/test_tree/ "}},
+		{"missing", "test/missing/", nil, false, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Files(tt.prefix, tt.globs, tt.dirsOnly)
+			if !reflect.DeepEqual(got, tt.list) {
+				t.Errorf("Files() got = %v, want %v", got, tt.list)
+			}
+		})
+	}
+}
+
 func TestSortForCompletion(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -82,9 +82,9 @@ func Synopsis(scriptName, name string, args []SynopsisArg, options []*option.Opt
 		txt := ""
 		wrap := wrapFn(!opt.IsRequired, "[", "]")
 		switch opt.OptType {
-		case option.BoolType, option.StringType, option.IntType, option.Float64Type:
+		case option.BoolType, option.StringType, option.IntType, option.Float64Type, option.DurationType, option.ValueType:
 			txt += wrap(opt.HelpSynopsis)
-		case option.StringRepeatType, option.IntRepeatType, option.StringMapType:
+		case option.StringRepeatType, option.IntRepeatType, option.StringMapType, option.DurationRepeatType, option.ValueRepeatType:
 			if opt.IsRequired {
 				wrap = wrapFn(opt.IsRequired, "<", ">")
 			}
@@ -92,10 +92,41 @@ func Synopsis(scriptName, name string, args []SynopsisArg, options []*option.Opt
 		}
 		return txt
 	}
+	// Mutually exclusive options are shown together where the first one of the group would be.
+	optionMap := map[string]*option.Option{}
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+	groupSynopsis := func(opt *option.Option) string {
+		members := []string{}
+		for _, name := range opt.ExclusiveGroup {
+			member, ok := optionMap[name]
+			if !ok {
+				continue
+			}
+			txt := member.HelpSynopsis
+			switch member.OptType {
+			case option.StringRepeatType, option.IntRepeatType, option.StringMapType, option.DurationRepeatType, option.ValueRepeatType:
+				txt += "..."
+			}
+			members = append(members, txt)
+		}
+		return fmt.Sprintf("(%s)", strings.Join(members, "|"))
+	}
+	shownGroups := map[string]bool{}
 	var out string
 	line := synopsisName
 	for _, option := range append(requiredOptions, normalOptions...) {
-		syn := optSynopsis(option)
+		var syn string
+		if len(option.ExclusiveGroup) > 0 {
+			if shownGroups[option.ExclusiveGroup[0]] {
+				continue
+			}
+			shownGroups[option.ExclusiveGroup[0]] = true
+			syn = groupSynopsis(option)
+		} else {
+			syn = optSynopsis(option)
+		}
 		// fmt.Printf("%d - %d - %d | %s | %s\n", len(line), len(syn), len(line)+len(syn), syn, line)
 		if len(line)+len(syn) > 80 {
 			out += line + "\n"
@@ -146,6 +177,29 @@ func CommandList(commandMap map[string]string) string {
 	return fmt.Sprintf("%s:\n%s", text.HelpCommandsHeader, out)
 }
 
+// EnvironmentList - Return a formatted list of the environment variables bound to the given options.
+func EnvironmentList(options []*option.Option) string {
+	envMap := map[string]string{}
+	names := []string{}
+	for _, opt := range options {
+		if _, ok := envMap[opt.EnvVar]; opt.EnvVar == "" || ok {
+			continue
+		}
+		envMap[opt.EnvVar] = opt.HelpSynopsis
+		names = append(names, opt.EnvVar)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	factor := longestStringLen(names)
+	out := ""
+	for _, name := range names {
+		out += indent(fmt.Sprintf("%s    %s\n", pad(true, name, factor), envMap[name]))
+	}
+	return fmt.Sprintf("%s:\n%s", text.HelpEnvironmentHeader, out)
+}
+
 // longestStringLen - Given a slice of strings it returns the length of the longest string in the slice
 func longestStringLen(s []string) int {
 	i := 0
@@ -149,6 +149,29 @@ func TestHelp(t *testing.T) {
     help.test log --bool|-b --float <float64> <--ii <int>>... --int <int>
                   <-m <key=value>>... <--ss <string>>... <-z <key=value>>...
                   <command> [<args>]
+`,
+		},
+		{
+			"Synopsis mutually exclusive", Synopsis(scriptName, "log", []SynopsisArg{},
+				func() []*option.Option {
+					json, yaml, table := false, false, false
+					options := []*option.Option{
+						intOpt(),
+						ssOpt(),
+						option.New("json", option.BoolType, &json),
+						option.New("yaml", option.BoolType, &yaml),
+						option.New("table", option.BoolType, &table),
+					}
+					for _, opt := range options[:2] {
+						opt.ExclusiveGroup = []string{"ss", "int"}
+					}
+					for _, opt := range options[2:] {
+						opt.ExclusiveGroup = []string{"json", "yaml", "table"}
+					}
+					return options
+				}(), []string{}),
+			`SYNOPSIS:
+    help.test log (--ss <string>...|--int <int>) (--json|--yaml|--table) [<args>]
 `,
 		},
 		{"OptionList nil", OptionList(nil, nil), ""},
@@ -292,6 +315,16 @@ OPTIONS:
 
     --string-repeat <my_value>    string repeat (default: [], env: STRING_REPEAT)
 
+`},
+		{"EnvironmentList nil", EnvironmentList(nil), ""},
+		{"EnvironmentList without env", EnvironmentList([]*option.Option{boolOpt(), intOpt()}), ""},
+		{"EnvironmentList", EnvironmentList([]*option.Option{
+			boolOpt().SetEnvVar("MYTOOL_BOOL"),
+			intOpt(),
+			floatOpt().SetEnvVar("FLOAT"),
+		}), `ENVIRONMENT:
+    FLOAT          --float <float64>
+    MYTOOL_BOOL    --bool|-b
 `},
 		{"CommandList", CommandList(nil), ""},
 		{"CommandList", CommandList(map[string]string{}), ""},
@@ -17,6 +17,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/DavidGamba/go-getoptions/text"
 )
@@ -27,13 +28,80 @@ var Logger = log.New(io.Discard, "DEBUG: ", log.Ldate|log.Ltime|log.Lshortfile)
 
 var ErrorMissingRequiredOption = errors.New("")
 
+// ErrorKind - Kind of error returned by Save.
+type ErrorKind int
+
+// Save error kinds
+const (
+	ConversionError   ErrorKind = iota // The argument can't be converted to the option type
+	InvalidValueError                  // The argument is not one of the ValidValues
+)
+
+// Error - Error returned by Save with the details of the failure.
+// The getoptions package converts it into its exported error types.
+type Error struct {
+	Kind        ErrorKind
+	Name        string   // Option name
+	UsedAlias   string   // Alias used to call the option
+	Value       string   // Argument that failed
+	OptType     Type     // Option type
+	ValidValues []string // Valid values for InvalidValueError
+	Err         error    // Underlying error
+	msg         string
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// conversionError - Returns an Error of ConversionError kind for the value that failed.
+func (opt *Option) conversionError(value string, err error, format string, a ...interface{}) error {
+	return &Error{
+		Kind:      ConversionError,
+		Name:      opt.Name,
+		UsedAlias: opt.UsedAlias,
+		Value:     value,
+		OptType:   opt.OptType,
+		Err:       err,
+		msg:       fmt.Sprintf(format, a...),
+	}
+}
+
 // Handler - Signature for the function that handles saving to the option.
 type Handler func(optName string, argument string, usedAlias string) error
 
 // ValueCompletionsFn - Function receiver for custom completions.
-// The `target` argument indicates "bash" or "zsh" for the completion targets.
+// The `target` argument indicates "bash", "zsh", "fish" or "powershell" for the completion targets.
 type ValueCompletionsFn func(target string, partialCompletion string) []string
 
+// ValueCompletionsContextFn - Function receiver for custom completions that
+// depend on the options parsed so far.
+// The `options` argument holds the options of the command being completed.
+type ValueCompletionsContextFn func(target string, options map[string]*Option, partialCompletion string) []string
+
+// Value - Interface for user defined option types.
+// It mirrors the standard library's flag.Value interface.
+type Value interface {
+	String() string
+	Set(string) error
+}
+
+// SourceKind - Indicates where the value of an option came from.
+type SourceKind int
+
+// Value sources
+const (
+	SourceDefault SourceKind = iota
+	SourceCLI
+	SourceEnv
+	SourceConfig
+	SourceSetValue
+)
+
 // Type - Indicates the type of option.
 type Type int
 
@@ -55,30 +123,74 @@ const (
 	Float64RepeatType
 
 	StringMapType
+
+	DurationType
+	DurationOptionalType
+	DurationRepeatType
+
+	ValueType
+	ValueRepeatType
 )
 
+var typeNames = map[Type]string{
+	BoolType:             "bool",
+	IncrementType:        "increment",
+	StringType:           "string",
+	IntType:              "int",
+	Float64Type:          "float64",
+	StringOptionalType:   "string-optional",
+	IntOptionalType:      "int-optional",
+	Float64OptionalType:  "float64-optional",
+	StringRepeatType:     "string-slice",
+	IntRepeatType:        "int-slice",
+	Float64RepeatType:    "float64-slice",
+	StringMapType:        "string-map",
+	DurationType:         "duration",
+	DurationOptionalType: "duration-optional",
+	DurationRepeatType:   "duration-slice",
+	ValueType:            "value",
+	ValueRepeatType:      "value-slice",
+}
+
+// String - Returns the name of the type.
+func (t Type) String() string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("Type(%d)", int(t))
+}
+
 // Option - main object
 type Option struct {
 	Name           string
 	Aliases        []string
-	EnvVar         string  // Env Var that sets the option value
-	Called         bool    // Indicates if the option was passed on the command line
-	UsedAlias      string  // Alias/Env var used when the option was called
-	Handler        Handler // method used to handle the option
-	IsOptional     bool    // Indicates if an option has an optional argument
-	MapKeysToLower bool    // Indicates if the option of map type has it keys set ToLower
-	OptType        Type    // Option Type
-	MinArgs        int     // minimum args when using multi
-	MaxArgs        int     // maximum args when using multi
+	EnvVar         string     // Env Var that sets the option value
+	EnvError       error      // Error reading the Env Var value, reported at Parse time
+	EnvDisabled    bool       // Skips the automatic Env Var binding
+	Called         bool       // Indicates if the option was passed on the command line
+	UsedAlias      string     // Alias/Env var used when the option was called
+	SourceKind     SourceKind // Where the option value came from
+	SourceName     string     // Alias, env var or file that set the option value
+	Handler        Handler    // method used to handle the option
+	IsOptional     bool       // Indicates if an option has an optional argument
+	MapKeysToLower bool       // Indicates if the option of map type has it keys set ToLower
+	OptType        Type       // Option Type
+	MinArgs        int        // minimum args when using multi
+	MaxArgs        int        // maximum args when using multi
 
 	IsRequired    bool   // Indicates if the option is required
 	IsRequiredErr string // Error message for the required option
 
+	ExclusiveGroup []string // Names of the options that can't be called together with this one, including itself
+
+	EnvReset func() // Restores the value set before the Env Var value, called when the CLI sets the option
+
 	// SuggestedValues used for completions, suggestions don't necessarily limit
 	// the values you are able to use
-	SuggestedValues   []string
-	ValidValues       []string // ValidValues that can be passed to Save
-	SuggestedValuesFn ValueCompletionsFn
+	SuggestedValues          []string
+	ValidValues              []string // ValidValues that can be passed to Save
+	SuggestedValuesFn        ValueCompletionsFn
+	SuggestedValuesContextFn ValueCompletionsContextFn
 
 	// Help
 	DefaultStr   string // String representation of default value
@@ -89,14 +201,17 @@ type Option struct {
 	boolDefault bool // copy of bool default value
 
 	// Pointer receivers:
-	pBool     *bool              // receiver for bool pointer
-	pString   *string            // receiver for string pointer
-	pInt      *int               // receiver for int pointer
-	pFloat64  *float64           // receiver for float64 pointer
-	pStringS  *[]string          // receiver for string slice pointer
-	pIntS     *[]int             // receiver for int slice pointer
-	pFloat64S *[]float64         // receiver for float64 slice pointer
-	pStringM  *map[string]string // receiver for string map pointer
+	pBool      *bool              // receiver for bool pointer
+	pString    *string            // receiver for string pointer
+	pInt       *int               // receiver for int pointer
+	pFloat64   *float64           // receiver for float64 pointer
+	pStringS   *[]string          // receiver for string slice pointer
+	pIntS      *[]int             // receiver for int slice pointer
+	pFloat64S  *[]float64         // receiver for float64 slice pointer
+	pStringM   *map[string]string // receiver for string map pointer
+	pDuration  *time.Duration     // receiver for duration pointer
+	pDurationS *[]time.Duration   // receiver for duration slice pointer
+	pValue     Value              // receiver for user defined values
 
 	Unknown bool // Temporary marker used during parsing
 
@@ -176,6 +291,37 @@ func New(name string, optType Type, data interface{}) *Option {
 		opt.DefaultStr = "{}"
 		opt.MinArgs = 1
 		opt.MaxArgs = 1 // By default we only allow one argument at a time
+	case DurationType:
+		opt.HelpArgName = "duration"
+		opt.pDuration = data.(*time.Duration)
+		opt.DefaultStr = data.(*time.Duration).String()
+		opt.MinArgs = 1
+		opt.MaxArgs = 1
+	case DurationOptionalType:
+		opt.HelpArgName = "duration"
+		opt.pDuration = data.(*time.Duration)
+		opt.DefaultStr = data.(*time.Duration).String()
+		opt.MinArgs = 0
+		opt.MaxArgs = 1
+		opt.IsOptional = true
+	case DurationRepeatType:
+		opt.HelpArgName = "duration"
+		opt.pDurationS = data.(*[]time.Duration)
+		opt.DefaultStr = "[]"
+		opt.MinArgs = 1
+		opt.MaxArgs = 1 // By default we only allow one argument at a time
+	case ValueType:
+		opt.HelpArgName = "value"
+		opt.pValue = data.(Value)
+		opt.DefaultStr = data.(Value).String()
+		opt.MinArgs = 1
+		opt.MaxArgs = 1
+	case ValueRepeatType:
+		opt.HelpArgName = "value"
+		opt.pValue = data.(Value)
+		opt.DefaultStr = data.(Value).String()
+		opt.MinArgs = 1
+		opt.MaxArgs = 1 // By default we only allow one argument at a time
 	case IncrementType:
 		opt.pInt = data.(*int)
 		opt.DefaultStr = fmt.Sprintf("%d", *data.(*int))
@@ -244,6 +390,16 @@ func (opt *Option) Value() interface{} {
 		return *opt.pFloat64S
 	case StringMapType:
 		return *opt.pStringM
+	case DurationType, DurationOptionalType:
+		return *opt.pDuration
+	case DurationRepeatType:
+		return *opt.pDurationS
+	case ValueType, ValueRepeatType:
+		// Same contract as the standard library's flag.Getter
+		if g, ok := opt.pValue.(interface{ Get() interface{} }); ok {
+			return g.Get()
+		}
+		return opt.pValue
 	default: // BoolType:
 		return *opt.pBool
 	}
@@ -308,6 +464,13 @@ func (opt *Option) SetCalled(usedAlias string) *Option {
 	return opt
 }
 
+// SetSource - Records where the option value came from.
+func (opt *Option) SetSource(kind SourceKind, name string) *Option {
+	opt.SourceKind = kind
+	opt.SourceName = name
+	return opt
+}
+
 // SetBool - Set the option's data.
 func (opt *Option) SetBool(b bool) *Option {
 	*opt.pBool = b
@@ -361,6 +524,18 @@ func (opt *Option) SetFloat64Slice(s []float64) *Option {
 	return opt
 }
 
+// SetDuration - Set the option's data.
+func (opt *Option) SetDuration(d time.Duration) *Option {
+	*opt.pDuration = d
+	return opt
+}
+
+// SetDurationSlice - Set the option's data.
+func (opt *Option) SetDurationSlice(s []time.Duration) *Option {
+	*opt.pDurationS = s
+	return opt
+}
+
 // SetKeyValueToStringMap - Set the option's data.
 func (opt *Option) SetKeyValueToStringMap(k, v string) *Option {
 	if opt.MapKeysToLower {
@@ -371,6 +546,59 @@ func (opt *Option) SetKeyValueToStringMap(k, v string) *Option {
 	return opt
 }
 
+// Snapshot - Returns a function that restores the current value of the option.
+// User defined values are restored if they implement `Snapshot() func()`,
+// otherwise it returns nil.
+func (opt *Option) Snapshot() func() {
+	switch opt.OptType {
+	case BoolType:
+		b := *opt.pBool
+		return func() { opt.SetBool(b) }
+	case StringType, StringOptionalType:
+		s := *opt.pString
+		return func() { opt.SetString(s) }
+	case IntType, IntOptionalType, IncrementType:
+		i := *opt.pInt
+		return func() { opt.SetInt(i) }
+	case Float64Type, Float64OptionalType:
+		f := *opt.pFloat64
+		return func() { opt.SetFloat64(f) }
+	case DurationType, DurationOptionalType:
+		d := *opt.pDuration
+		return func() { opt.SetDuration(d) }
+	case StringRepeatType:
+		s := append([]string{}, *opt.pStringS...)
+		return func() { opt.SetStringSlice(s) }
+	case IntRepeatType:
+		s := append([]int{}, *opt.pIntS...)
+		return func() { opt.SetIntSlice(s) }
+	case Float64RepeatType:
+		s := append([]float64{}, *opt.pFloat64S...)
+		return func() { opt.SetFloat64Slice(s) }
+	case DurationRepeatType:
+		s := append([]time.Duration{}, *opt.pDurationS...)
+		return func() { opt.SetDurationSlice(s) }
+	case StringMapType:
+		m := map[string]string{}
+		for k, v := range *opt.pStringM {
+			m[k] = v
+		}
+		return func() {
+			for k := range *opt.pStringM {
+				delete(*opt.pStringM, k)
+			}
+			for k, v := range m {
+				(*opt.pStringM)[k] = v
+			}
+		}
+	case ValueType, ValueRepeatType:
+		if v, ok := opt.pValue.(interface{ Snapshot() func() }); ok {
+			return v.Snapshot()
+		}
+	}
+	return nil
+}
+
 // stringSliceIndex - indicates if an element is found in the slice and what its index is
 func stringSliceIndex(ss []string, e string) (int, bool) {
 	for i, s := range ss {
@@ -397,8 +625,15 @@ func (opt *Option) Save(a ...string) error {
 		if len(opt.ValidValues) > 0 {
 			_, ok := stringSliceIndex(opt.ValidValues, e)
 			if !ok {
-				// TODO: convert to text variable
-				return fmt.Errorf("wrong value for option '%s', valid values are %q", opt.Name, opt.ValidValues)
+				return &Error{
+					Kind:        InvalidValueError,
+					Name:        opt.Name,
+					UsedAlias:   opt.UsedAlias,
+					Value:       e,
+					OptType:     opt.OptType,
+					ValidValues: opt.ValidValues,
+					msg:         fmt.Sprintf(text.ErrorInvalidValue, opt.Name, opt.ValidValues),
+				}
 			}
 		}
 	}
@@ -410,8 +645,7 @@ func (opt *Option) Save(a ...string) error {
 	case IntType, IntOptionalType:
 		i, err := strconv.Atoi(a[0])
 		if err != nil {
-			// TODO: Create error type for use in tests with errors.Is
-			return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, a[0])
+			return opt.conversionError(a[0], err, text.ErrorConvertToInt, opt.UsedAlias, a[0])
 		}
 		opt.SetInt(i)
 		return nil
@@ -419,8 +653,7 @@ func (opt *Option) Save(a ...string) error {
 		// TODO: Read the different errors when parsing float
 		f, err := strconv.ParseFloat(a[0], 64)
 		if err != nil {
-			// TODO: Create error type for use in tests with errors.Is
-			return fmt.Errorf(text.ErrorConvertToFloat64, opt.UsedAlias, a[0])
+			return opt.conversionError(a[0], err, text.ErrorConvertToFloat64, opt.UsedAlias, a[0])
 		}
 		opt.SetFloat64(f)
 		return nil
@@ -438,12 +671,12 @@ func (opt *Option) Save(a ...string) error {
 				in1, err := strconv.Atoi(n1)
 				if err != nil {
 					// TODO: Create new error description for this error.
-					return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, e)
+					return opt.conversionError(e, err, text.ErrorConvertToInt, opt.UsedAlias, e)
 				}
 				in2, err := strconv.Atoi(n2)
 				if err != nil {
 					// TODO: Create new error description for this error.
-					return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, e)
+					return opt.conversionError(e, err, text.ErrorConvertToInt, opt.UsedAlias, e)
 				}
 				if in1 < in2 {
 					for j := in1; j <= in2; j++ {
@@ -451,13 +684,12 @@ func (opt *Option) Save(a ...string) error {
 					}
 				} else {
 					// TODO: Create new error description for this error.
-					return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, e)
+					return opt.conversionError(e, nil, text.ErrorConvertToInt, opt.UsedAlias, e)
 				}
 			} else {
 				i, err := strconv.Atoi(e)
 				if err != nil {
-					// TODO: Create error type for use in tests with errors.Is
-					return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, e)
+					return opt.conversionError(e, err, text.ErrorConvertToInt, opt.UsedAlias, e)
 				}
 				ii = append(ii, i)
 			}
@@ -469,19 +701,49 @@ func (opt *Option) Save(a ...string) error {
 		for _, e := range a {
 			f, err := strconv.ParseFloat(e, 64)
 			if err != nil {
-				// TODO: Create error type for use in tests with errors.Is
-				return fmt.Errorf(text.ErrorConvertToFloat64, opt.UsedAlias, e)
+				return opt.conversionError(e, err, text.ErrorConvertToFloat64, opt.UsedAlias, e)
 			}
 			ff = append(ff, f)
 		}
 		opt.SetFloat64Slice(append(*opt.pFloat64S, ff...))
 		return nil
+	case DurationType, DurationOptionalType:
+		d, err := time.ParseDuration(a[0])
+		if err != nil {
+			return opt.conversionError(a[0], err, text.ErrorConvertToDuration, opt.UsedAlias, a[0])
+		}
+		opt.SetDuration(d)
+		return nil
+	case DurationRepeatType:
+		var dd []time.Duration
+		for _, e := range a {
+			d, err := time.ParseDuration(e)
+			if err != nil {
+				return opt.conversionError(e, err, text.ErrorConvertToDuration, opt.UsedAlias, e)
+			}
+			dd = append(dd, d)
+		}
+		opt.SetDurationSlice(append(*opt.pDurationS, dd...))
+		return nil
+	case ValueType:
+		err := opt.pValue.Set(a[0])
+		if err != nil {
+			return opt.conversionError(a[0], err, text.ErrorConvertToValue, opt.UsedAlias, a[0], err)
+		}
+		return nil
+	case ValueRepeatType:
+		for _, e := range a {
+			err := opt.pValue.Set(e)
+			if err != nil {
+				return opt.conversionError(e, err, text.ErrorConvertToValue, opt.UsedAlias, e, err)
+			}
+		}
+		return nil
 	case StringMapType:
 		for _, e := range a {
 			keyValue := strings.Split(e, "=")
 			if len(keyValue) < 2 {
-				// TODO: Create error type for use in tests with errors.Is
-				return fmt.Errorf(text.ErrorArgumentIsNotKeyValue, opt.UsedAlias)
+				return opt.conversionError(e, nil, text.ErrorArgumentIsNotKeyValue, opt.UsedAlias)
 			}
 			opt.SetKeyValueToStringMap(keyValue[0], keyValue[1])
 		}
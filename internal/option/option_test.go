@@ -12,7 +12,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/DavidGamba/go-getoptions/text"
 )
@@ -320,6 +323,49 @@ func TestOption(t *testing.T) {
 			map[string]string{},
 			fmt.Errorf(text.ErrorArgumentIsNotKeyValue, ""),
 		},
+
+		{"duration", func() *Option {
+			d := time.Duration(0)
+			return New("help", DurationType, &d)
+		}(), []string{"1m30s"}, 90 * time.Second, nil},
+		{
+			"duration error", func() *Option {
+				d := time.Duration(0)
+				return New("help", DurationType, &d).SetCalled("timeout")
+			}(),
+			[]string{"123x"},
+			time.Duration(0),
+			fmt.Errorf(text.ErrorConvertToDuration, "timeout", "123x"),
+		},
+		{"duration optional", func() *Option {
+			d := time.Duration(0)
+			return New("help", DurationOptionalType, &d).SetDuration(time.Second)
+		}(), []string{}, time.Second, nil},
+		{"duration slice", func() *Option {
+			dd := []time.Duration{}
+			return New("help", DurationRepeatType, &dd)
+		}(), []string{"1s", "2ms"}, []time.Duration{time.Second, 2 * time.Millisecond}, nil},
+		{
+			"duration slice error", func() *Option {
+				dd := []time.Duration{}
+				return New("help", DurationRepeatType, &dd)
+			}(),
+			[]string{"x"},
+			[]time.Duration{},
+			fmt.Errorf(text.ErrorConvertToDuration, "", "x"),
+		},
+		{"value", func() *Option {
+			return New("help", ValueType, &testValue{})
+		}(), []string{"a"}, &testValue{s: "a"}, nil},
+		{"value error", func() *Option {
+			return New("help", ValueType, &testValue{})
+		}(), []string{"error"}, &testValue{}, fmt.Errorf(text.ErrorConvertToValue, "", "error", "invalid")},
+		{"value slice", func() *Option {
+			return New("help", ValueRepeatType, &testSliceValue{})
+		}(), []string{"a", "b"}, []string{"a", "b"}, nil},
+		{"value slice error", func() *Option {
+			return New("help", ValueRepeatType, &testSliceValue{})
+		}(), []string{"a", "error"}, []string{"a"}, fmt.Errorf(text.ErrorConvertToValue, "", "error", "invalid")},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -436,6 +482,26 @@ func TestOther(t *testing.T) {
 	if opt.HelpSynopsis != "--help <int>..." {
 		t.Errorf("got = '%#v', want '%#v'", opt.HelpSynopsis, "--help <int>...")
 	}
+
+	if IntRepeatType.String() != "int-slice" {
+		t.Errorf("got = '%#v', want '%#v'", IntRepeatType.String(), "int-slice")
+	}
+	if Type(99).String() != "Type(99)" {
+		t.Errorf("got = '%#v', want '%#v'", Type(99).String(), "Type(99)")
+	}
+
+	opt = New("help", IntType, &i).SetSource(SourceEnv, "ENV_VAR")
+	if opt.SourceKind != SourceEnv || opt.SourceName != "ENV_VAR" {
+		t.Errorf("got = '%#v', '%#v', want '%#v', '%#v'", opt.SourceKind, opt.SourceName, SourceEnv, "ENV_VAR")
+	}
+	err := opt.Save("x")
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != ConversionError || e.Value != "x" || e.OptType != IntType {
+		t.Errorf("got = '%#v'", err)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("got = '%#v', want wrapped '%#v'", errors.Unwrap(err), strconv.ErrSyntax)
+	}
 }
 
 func TestValidateMinMaxArgs(t *testing.T) {
@@ -481,3 +547,89 @@ func TestValidateMinMaxArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapshot(t *testing.T) {
+	ss := []string{"a"}
+	ii := []int{1}
+	ff := []float64{1.1}
+	dd := []time.Duration{time.Second}
+	m := map[string]string{"a": "1"}
+	inc := 3
+	b := false
+	str := "a"
+	i := 1
+	f := 1.1
+	d := time.Second
+	sv := &testSliceValue{ss: []string{"a"}}
+	tests := []struct {
+		name     string
+		option   *Option
+		input    []string
+		value    func() interface{}
+		expected interface{}
+	}{
+		{"string slice", New("ss", StringRepeatType, &ss), []string{"b"}, func() interface{} { return ss }, []string{"a"}},
+		{"int slice", New("ii", IntRepeatType, &ii), []string{"2"}, func() interface{} { return ii }, []int{1}},
+		{"float64 slice", New("ff", Float64RepeatType, &ff), []string{"2.2"}, func() interface{} { return ff }, []float64{1.1}},
+		{"duration slice", New("dd", DurationRepeatType, &dd), []string{"1m"}, func() interface{} { return dd }, []time.Duration{time.Second}},
+		{"string map", New("m", StringMapType, &m), []string{"b=2"}, func() interface{} { return m }, map[string]string{"a": "1"}},
+		{"increment", New("v", IncrementType, &inc), []string{""}, func() interface{} { return inc }, 3},
+		{"bool", New("b", BoolType, &b), []string{"true"}, func() interface{} { return b }, false},
+		{"string", New("s", StringType, &str), []string{"b"}, func() interface{} { return str }, "a"},
+		{"int", New("i", IntType, &i), []string{"2"}, func() interface{} { return i }, 1},
+		{"float64", New("f", Float64Type, &f), []string{"2.2"}, func() interface{} { return f }, 1.1},
+		{"duration", New("d", DurationType, &d), []string{"1m"}, func() interface{} { return d }, time.Second},
+		{"value slice", New("vv", ValueRepeatType, sv), []string{"b"}, func() interface{} { return sv.ss }, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore := tt.option.Snapshot()
+			err := tt.option.Save(tt.input...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			restore()
+			if !reflect.DeepEqual(tt.value(), tt.expected) {
+				t.Errorf("Unexpected value: %#v", tt.value())
+			}
+		})
+	}
+
+	v := &testValue{}
+	if New("v", ValueType, v).Snapshot() != nil {
+		t.Errorf("Unexpected snapshot for value without Snapshot method")
+	}
+}
+
+// testSliceValue - user defined repeat Value with Get and Snapshot methods.
+type testSliceValue struct{ ss []string }
+
+func (v *testSliceValue) String() string { return strings.Join(v.ss, ",") }
+
+func (v *testSliceValue) Set(s string) error {
+	if s == "error" {
+		return errors.New("invalid")
+	}
+	v.ss = append(v.ss, s)
+	return nil
+}
+
+func (v *testSliceValue) Get() interface{} { return v.ss }
+
+func (v *testSliceValue) Snapshot() func() {
+	ss := append([]string{}, v.ss...)
+	return func() { v.ss = ss }
+}
+
+// testValue - user defined Value without a Snapshot method.
+type testValue struct{ s string }
+
+func (v *testValue) String() string { return v.s }
+
+func (v *testValue) Set(s string) error {
+	if s == "error" {
+		return errors.New("invalid")
+	}
+	v.s = s
+	return nil
+}
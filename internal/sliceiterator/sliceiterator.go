@@ -64,6 +64,11 @@ func (a *Iterator) IsLast() bool {
 	return a.idx == len(*a.data)-1
 }
 
+// IsNextLast - Tells if the next element is the last.
+func (a *Iterator) IsNextLast() bool {
+	return a.idx+1 == len(*a.data)-1
+}
+
 // Remaining - Get all remaining values index inclusive.
 func (a *Iterator) Remaining() []string {
 	if a.idx >= len(*a.data) {
@@ -50,10 +50,16 @@ func TestIterator(t *testing.T) {
 			if i.IsLast() {
 				t.Errorf("not last\n")
 			}
+			if !i.IsNextLast() {
+				t.Errorf("next is last\n")
+			}
 		}
 		if i.Index() == 3 && !i.IsLast() {
 			t.Errorf("last not marked properly\n")
 		}
+		if i.Index() != 2 && i.IsNextLast() {
+			t.Errorf("next last not marked properly\n")
+		}
 	}
 	if i.ExistsNext() {
 		t.Errorf("wrong ExistsNext: idx %d, size %d", i.Index(), i.Size())
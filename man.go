@@ -0,0 +1,180 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2025  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/DavidGamba/go-getoptions/internal/help"
+	"github.com/DavidGamba/go-getoptions/internal/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// ManPage - Writes a roff man page for the current command.
+//
+// The page has the same sections as the built in help: NAME, SYNOPSIS,
+// COMMANDS, ARGUMENTS, REQUIRED PARAMETERS, OPTIONS and ENVIRONMENT.
+// Section headers use the `text.Help*Header` variables.
+func (gopt *GetOpt) ManPage(w io.Writer, section int) error {
+	_, err := io.WriteString(w, manPage(gopt.programTree, section))
+	return err
+}
+
+// ManPages - Writes one roff man page per command to the given directory.
+//
+// Files are named after the command path, for example: `mytool.1`,
+// `mytool-deploy.1` and `mytool-deploy-rollback.1`.
+// The help command doesn't get a page.
+func (gopt *GetOpt) ManPages(dir string, section int) error {
+	var err error
+	runOnParentAndChildrenCommands(gopt.programTree, func(n *programTree) {
+		if err != nil || (n.HelpCommandName != "" && n.Name == n.HelpCommandName && n.Parent != nil) {
+			return
+		}
+		filename := filepath.Join(dir, fmt.Sprintf("%s.%d", manPageName(n), section))
+		err = os.WriteFile(filename, []byte(manPage(n, section)), 0644)
+	})
+	return err
+}
+
+// manPageName - Returns the command path joined with dashes.
+func manPageName(n *programTree) string {
+	return strings.ReplaceAll(getCurrentNodeName(n), " ", "-")
+}
+
+// manEscape - Escapes text so roff doesn't interpret it.
+func manEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// manItem - Returns a tagged paragraph.
+func manItem(tag, body string) string {
+	out := fmt.Sprintf(".TP\n.B %s\n", manEscape(tag))
+	if body != "" {
+		out += manEscape(body) + "\n"
+	}
+	return out
+}
+
+func manPage(n *programTree, section int) string {
+	options := nodeOptions(n)
+	commands := []string{}
+	for _, command := range nodeCommands(n) {
+		commands = append(commands, command.Name)
+	}
+	scriptName := getCurrentNodeName(n)
+
+	out := fmt.Sprintf(".TH \"%s\" \"%d\"\n", manEscape(strings.ToUpper(manPageName(n))), section)
+
+	out += fmt.Sprintf(".SH %s\n", text.HelpNameHeader)
+	out += manEscape(manPageName(n))
+	if n.Description != "" {
+		out += ` \- ` + manEscape(strings.ReplaceAll(n.Description, "\n", " "))
+	}
+	out += "\n"
+
+	// Reuse the help synopsis, dropping its header and indentation.
+	synopsis := strings.Split(strings.TrimSuffix(help.Synopsis("", scriptName, n.SynopsisArgs, options, commands), "\n"), "\n")[1:]
+	for i := range synopsis {
+		synopsis[i] = strings.TrimSpace(synopsis[i])
+	}
+	out += fmt.Sprintf(".SH %s\n", text.HelpSynopsisHeader)
+	out += fmt.Sprintf(".B %s\n", manEscape(scriptName))
+	out += manEscape(strings.TrimSpace(strings.TrimPrefix(strings.Join(synopsis, " "), scriptName))) + "\n"
+
+	if len(commands) > 0 {
+		out += fmt.Sprintf(".SH %s\n", text.HelpCommandsHeader)
+		for _, name := range commands {
+			out += manItem(name, n.ChildCommands[name].Description)
+		}
+	}
+
+	args := []help.SynopsisArg{}
+	for _, arg := range n.SynopsisArgs {
+		if arg.Arg != "" && arg.Description != "" {
+			args = append(args, arg)
+		}
+	}
+	if len(args) > 0 {
+		out += fmt.Sprintf(".SH %s\n", text.HelpArgumentsHeader)
+		for _, arg := range args {
+			out += manItem(arg.Arg, arg.Description)
+		}
+	}
+
+	normalOptions := []*option.Option{}
+	requiredOptions := []*option.Option{}
+	for _, opt := range options {
+		if opt.IsRequired {
+			requiredOptions = append(requiredOptions, opt)
+		} else {
+			normalOptions = append(normalOptions, opt)
+		}
+	}
+	option.Sort(normalOptions)
+	option.Sort(requiredOptions)
+	optionText := func(opt *option.Option) string {
+		details := []string{}
+		if !opt.IsRequired {
+			details = append(details, "default: "+opt.DefaultStr)
+		}
+		if opt.EnvVar != "" {
+			details = append(details, "env: "+opt.EnvVar)
+		}
+		description := opt.Description
+		if len(details) > 0 {
+			if description != "" {
+				description += " "
+			}
+			description += "(" + strings.Join(details, ", ") + ")"
+		}
+		return manItem(opt.HelpSynopsis, description)
+	}
+	if len(requiredOptions) > 0 {
+		out += fmt.Sprintf(".SH %s\n", text.HelpRequiredOptionsHeader)
+		for _, opt := range requiredOptions {
+			out += optionText(opt)
+		}
+	}
+	if len(normalOptions) > 0 {
+		out += fmt.Sprintf(".SH %s\n", text.HelpOptionsHeader)
+		for _, opt := range normalOptions {
+			out += optionText(opt)
+		}
+	}
+
+	envOptions := []*option.Option{}
+	for _, opt := range append(requiredOptions, normalOptions...) {
+		if opt.EnvVar != "" {
+			envOptions = append(envOptions, opt)
+		}
+	}
+	if len(envOptions) > 0 {
+		sort.SliceStable(envOptions, func(i, j int) bool { return envOptions[i].EnvVar < envOptions[j].EnvVar })
+		out += fmt.Sprintf(".SH %s\n", text.HelpEnvironmentHeader)
+		for _, opt := range envOptions {
+			out += manItem(opt.EnvVar, opt.HelpSynopsis)
+		}
+	}
+
+	return out
+}
@@ -11,11 +11,15 @@ package getoptions_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -1184,6 +1188,318 @@ func TestGetOptFloat64(t *testing.T) {
 	})
 }
 
+func TestGetOptDuration(t *testing.T) {
+	setup := func() *getoptions.GetOpt {
+		opt := getoptions.New()
+		opt.Duration("timeout", 0, opt.Alias("t"))
+		return opt
+	}
+
+	cases := []struct {
+		opt    *getoptions.GetOpt
+		option string
+		input  []string
+		value  time.Duration
+	}{
+		{
+			setup(),
+			"timeout",
+			[]string{"--timeout=1m30s"},
+			90 * time.Second,
+		},
+		{
+			setup(),
+			"timeout",
+			[]string{"--timeout", "500ms", "world"},
+			500 * time.Millisecond,
+		},
+		{
+			setup(),
+			"timeout",
+			[]string{"-t", "2h"},
+			2 * time.Hour,
+		},
+	}
+	for _, c := range cases {
+		_, err := c.opt.Parse(c.input)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if c.opt.Value(c.option) != c.value {
+			t.Errorf("Wrong value: %v != %v", c.opt.Value(c.option), c.value)
+		}
+	}
+
+	t.Run("Cast errors", func(t *testing.T) {
+		opt := getoptions.New()
+		opt.Duration("timeout", 0)
+		_, err := opt.Parse([]string{"--timeout", "hello"})
+		if err == nil {
+			t.Errorf("Duration cast didn't raise errors")
+		}
+		if err != nil && err.Error() != fmt.Sprintf(text.ErrorConvertToDuration, "timeout", "hello") {
+			t.Errorf("Error string didn't match expected value '%s'", err)
+		}
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		opt := getoptions.New()
+		d := opt.DurationOptional("timeout", 5*time.Second)
+		_, err := opt.Parse([]string{"--timeout"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if *d != 5*time.Second || !opt.Called("timeout") {
+			t.Errorf("Wrong value: %v", *d)
+		}
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		opt := getoptions.New()
+		dd := opt.DurationSlice("retry", 1, 3)
+		remaining, err := opt.Parse([]string{"--retry", "1s", "5s", "world", "--retry", "1m"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		expected := []time.Duration{time.Second, 5 * time.Second, time.Minute}
+		if !reflect.DeepEqual(*dd, expected) {
+			t.Errorf("Wrong value: %v != %v", *dd, expected)
+		}
+		if !reflect.DeepEqual(remaining, []string{"world"}) {
+			t.Errorf("Wrong remaining: %v", remaining)
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		opt := getoptions.New()
+		opt.Duration("timeout", 30*time.Second)
+		expected := `    --timeout <duration>    (default: 30s)
+
+`
+		got := opt.Help(getoptions.HelpOptionList)
+		if !strings.HasSuffix(got, expected) {
+			t.Errorf("Unexpected help:\n%s", firstDiff(got, expected))
+		}
+	})
+}
+
+// ipValue - user defined option type used to test opt.Var.
+type ipValue struct {
+	ip net.IP
+}
+
+func (v *ipValue) String() string {
+	if v.ip == nil {
+		return ""
+	}
+	return v.ip.String()
+}
+
+func (v *ipValue) Set(s string) error {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return fmt.Errorf("invalid IP")
+	}
+	v.ip = ip
+	return nil
+}
+
+func TestGetOptVar(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		ip := &ipValue{ip: net.ParseIP("127.0.0.1")}
+		opt := getoptions.New()
+		opt.Var(ip, "ip", opt.Alias("i"))
+		_, err := opt.Parse([]string{"-i", "10.0.0.1"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if ip.String() != "10.0.0.1" {
+			t.Errorf("Wrong value: %v", ip)
+		}
+		if opt.Value("ip").(*ipValue) != ip {
+			t.Errorf("Wrong value: %v", opt.Value("ip"))
+		}
+	})
+
+	t.Run("Set errors", func(t *testing.T) {
+		opt := getoptions.New()
+		opt.Var(&ipValue{}, "ip")
+		_, err := opt.Parse([]string{"--ip", "hello"})
+		if err == nil {
+			t.Errorf("Var didn't raise errors")
+		}
+		if err != nil && err.Error() != fmt.Sprintf(text.ErrorConvertToValue, "ip", "hello", "invalid IP") {
+			t.Errorf("Error string didn't match expected value '%s'", err)
+		}
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		opt := getoptions.New()
+		opt.Var(&ipValue{}, "ip", opt.Required())
+		_, err := opt.Parse([]string{})
+		if err == nil || !errors.Is(err, getoptions.ErrorParsing) {
+			t.Errorf("Required didn't raise errors: %v", err)
+		}
+	})
+
+	t.Run("ValidValues", func(t *testing.T) {
+		opt := getoptions.New()
+		opt.Var(&ipValue{}, "ip", opt.ValidValues("10.0.0.1"))
+		_, err := opt.Parse([]string{"--ip", "10.0.0.2"})
+		if err == nil {
+			t.Errorf("ValidValues didn't raise errors")
+		}
+	})
+
+	t.Run("GetEnv", func(t *testing.T) {
+		os.Setenv("_get_opt_env_ip", "10.0.0.3")
+		defer os.Unsetenv("_get_opt_env_ip")
+		ip := &ipValue{}
+		opt := getoptions.New()
+		opt.Var(ip, "ip", opt.GetEnv("_get_opt_env_ip"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if ip.String() != "10.0.0.3" || opt.CalledAs("ip") != "_get_opt_env_ip" {
+			t.Errorf("Wrong value: %v", ip)
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		opt := getoptions.New()
+		opt.Var(&ipValue{ip: net.ParseIP("127.0.0.1")}, "ip", opt.ArgName("address"))
+		expected := `    --ip <address>    (default: 127.0.0.1)
+
+`
+		got := opt.Help(getoptions.HelpOptionList)
+		if !strings.HasSuffix(got, expected) {
+			t.Errorf("Unexpected help:\n%s", firstDiff(got, expected))
+		}
+	})
+}
+
+func TestGenericOption(t *testing.T) {
+	parseLevel := func(s string) (uint8, error) {
+		i, err := strconv.ParseUint(s, 10, 8)
+		return uint8(i), err
+	}
+
+	t.Run("value", func(t *testing.T) {
+		opt := getoptions.New()
+		u := getoptions.Option(opt, "url", &url.URL{}, url.Parse, opt.Alias("u"))
+		l := getoptions.Option(opt, "level", 3, parseLevel)
+		_, err := opt.Parse([]string{"-u", "https://example.com/path"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if (*u).Host != "example.com" {
+			t.Errorf("Wrong value: %v", *u)
+		}
+		if *l != 3 || opt.Value("level").(uint8) != 3 {
+			t.Errorf("Wrong value: %v", *l)
+		}
+		if opt.Value("url").(*url.URL) != *u {
+			t.Errorf("Wrong value: %v", opt.Value("url"))
+		}
+	})
+
+	t.Run("Parse errors", func(t *testing.T) {
+		opt := getoptions.New()
+		getoptions.Option(opt, "level", 3, parseLevel)
+		_, err := opt.Parse([]string{"--level", "300"})
+		if err == nil {
+			t.Errorf("Option didn't raise errors")
+		}
+		if err != nil && !strings.HasPrefix(err.Error(), "Argument error for option 'level': Can't set value '300'") {
+			t.Errorf("Error string didn't match expected value '%s'", err)
+		}
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		opt := getoptions.New()
+		var ll []uint8
+		getoptions.SliceOptionVar(opt, &ll, "level", 1, 2, parseLevel, opt.Required())
+		remaining, err := opt.Parse([]string{"--level", "1", "2", "world", "--level=3"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(ll, []uint8{1, 2, 3}) {
+			t.Errorf("Wrong value: %v", ll)
+		}
+		if !reflect.DeepEqual(opt.Value("level"), []uint8{1, 2, 3}) {
+			t.Errorf("Wrong value: %v", opt.Value("level"))
+		}
+		if !reflect.DeepEqual(remaining, []string{"world"}) {
+			t.Errorf("Wrong remaining: %v", remaining)
+		}
+	})
+
+	t.Run("slice parse errors", func(t *testing.T) {
+		opt := getoptions.New()
+		getoptions.SliceOption(opt, "level", 1, 1, parseLevel)
+		_, err := opt.Parse([]string{"--level", "1", "--level", "x"})
+		if err == nil {
+			t.Errorf("Option didn't raise errors")
+		}
+		if err != nil && !strings.HasPrefix(err.Error(), "Argument error for option 'level': Can't set value 'x'") {
+			t.Errorf("Error string didn't match expected value '%s'", err)
+		}
+	})
+
+	t.Run("env var overridden by CLI", func(t *testing.T) {
+		t.Setenv("_get_opt_env_level", "5")
+		opt := getoptions.New()
+		l := getoptions.Option(opt, "level", 3, parseLevel, opt.GetEnv("_get_opt_env_level"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if *l != 5 {
+			t.Errorf("Wrong value: %v", *l)
+		}
+
+		opt = getoptions.New()
+		l = getoptions.Option(opt, "level", 3, parseLevel, opt.GetEnv("_get_opt_env_level"))
+		_, err = opt.Parse([]string{"--level", "7"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if *l != 7 {
+			t.Errorf("Wrong value: %v", *l)
+		}
+	})
+
+	t.Run("slice wrong min max", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("wrong min/max definition did not panic")
+			}
+		}()
+		opt := getoptions.New()
+		getoptions.SliceOption(opt, "level", 0, 1, parseLevel)
+	})
+
+	t.Run("help", func(t *testing.T) {
+		opt := getoptions.New()
+		getoptions.Option(opt, "level", 3, parseLevel, opt.ArgName("level"))
+		getoptions.SliceOption(opt, "ll", 1, 1, parseLevel)
+		preset := []uint8{1, 2}
+		getoptions.SliceOptionVar(opt, &preset, "preset", 1, 1, parseLevel)
+		expected := `    --level <level>     (default: 3)
+
+    --ll <value>        (default: [])
+
+    --preset <value>    (default: [1 2])
+
+`
+		got := opt.Help(getoptions.HelpOptionList)
+		if !strings.HasSuffix(got, expected) {
+			t.Errorf("Unexpected help:\n%s", firstDiff(got, expected))
+		}
+	})
+}
+
 // TODO: Allow passing : as the map divider
 func TestGetOptStringMap(t *testing.T) {
 	setup := func() *getoptions.GetOpt {
@@ -3440,9 +3756,14 @@ func TestGetEnv(t *testing.T) {
 		opt.IntVar(&v1, "opt1", 123, opt.GetEnv("_get_opt_env_test1"))
 		v2 := opt.Int("opt2", 123, opt.GetEnv("_get_opt_env_test2"))
 		_, err := opt.Parse([]string{})
-		// TODO: Handle errors when env vars don't have proper types
-		if err != nil {
-			t.Errorf("Unexpected error: %s", err)
+		if err == nil || !errors.Is(err, getoptions.ErrorParsing) {
+			t.Errorf("Expected parsing error: %v", err)
+		}
+		expected := getoptions.ErrorParsing.Error() +
+			fmt.Sprintf(text.ErrorEnvVar, "_get_opt_env_test1", fmt.Sprintf(text.ErrorConvertToInt, "opt1", "abc")) + "\n" +
+			fmt.Sprintf(text.ErrorEnvVar, "_get_opt_env_test2", fmt.Sprintf(text.ErrorConvertToInt, "opt2", "abc"))
+		if err != nil && err.Error() != expected {
+			t.Errorf("Error string didn't match expected value:\n%s\n%s", err.Error(), expected)
 		}
 		if v1 != 123 {
 			t.Errorf("Unexpected value: %d, %#v", v1, opt.Value("opt1"))
@@ -3453,6 +3774,21 @@ func TestGetEnv(t *testing.T) {
 		cleanup()
 	})
 
+	t.Run("int env error with help called", func(t *testing.T) {
+		setup("abc")
+		opt := getoptions.New()
+		opt.Int("opt1", 123, opt.GetEnv("_get_opt_env_test1"))
+		opt.HelpCommand("help")
+		_, err := opt.Parse([]string{"--help"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !opt.Called("help") {
+			t.Errorf("help not called")
+		}
+		cleanup()
+	})
+
 	/////////////////////////////////////////////////////////////////////////////
 	// Float64
 	/////////////////////////////////////////////////////////////////////////////
@@ -3496,58 +3832,561 @@ func TestGetEnv(t *testing.T) {
 	})
 }
 
-func TestAll(t *testing.T) {
-	var flag bool
-	var str string
-	var integer int
-	opt := getoptions.New()
-	opt.Bool("flag", false)
-	opt.BoolVar(&flag, "varflag", false)
-	opt.Bool("non-used-flag", false)
-	opt.String("string", "")
-	opt.StringVar(&str, "stringVar", "")
-	opt.Int("int", 0)
-	opt.IntVar(&integer, "intVar", 0)
-	opt.StringSlice("string-repeat", 1, 1)
-	opt.StringSlice("string-slice-multi", 1, 3)
-	opt.StringMap("string-map", 1, 1)
+func TestGetEnvLists(t *testing.T) {
+	cleanup := func() {
+		os.Unsetenv("_get_opt_env_list")
+		os.Unsetenv("_get_opt_env_list2")
+	}
 
-	remaining, err := opt.Parse([]string{
-		"hello",
-		"--flag",
-		"--varflag",
-		"happy",
-		"--string", "hello",
-		"--stringVar", "hello",
-		"--int", "123",
-		"--intVar", "123",
-		"--string-repeat", "hello", "--string-repeat", "world",
-		"--string-slice-multi", "hello", "happy", "--string-slice-multi", "world",
-		"--string-map", "hello=world", "--string-map", "server=name",
-		"world",
+	t.Run("string slice", func(t *testing.T) {
+		defer cleanup()
+		os.Setenv("_get_opt_env_list", `a, "b,c",d`)
+		opt := getoptions.New()
+		ss := opt.StringSlice("ss", 1, 99, opt.GetEnv("_get_opt_env_list"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(*ss, []string{"a", "b,c", "d"}) {
+			t.Errorf("Unexpected value: %#v", *ss)
+		}
+		if !opt.Called("ss") || opt.CalledAs("ss") != "_get_opt_env_list" {
+			t.Errorf("Unexpected called as: %s", opt.CalledAs("ss"))
+		}
 	})
-	if err != nil {
-		t.Errorf("Unexpected error: %s", err)
-	}
 
-	if !reflect.DeepEqual(remaining, []string{"hello", "happy", "world"}) {
-		t.Errorf("remaining didn't have expected value: %v != %v", remaining, []string{"hello", "happy", "world"})
-	}
+	t.Run("string slice overridden by CLI", func(t *testing.T) {
+		defer cleanup()
+		os.Setenv("_get_opt_env_list", "a,b")
+		opt := getoptions.New()
+		ss := opt.StringSlice("ss", 1, 99, opt.GetEnv("_get_opt_env_list"))
+		_, err := opt.Parse([]string{"--ss", "c", "--ss", "d"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(*ss, []string{"c", "d"}) {
+			t.Errorf("Unexpected value: %#v", *ss)
+		}
+	})
 
-	expected := map[string]interface{}{
-		"flag":               true,
-		"string":             "hello",
-		"int":                123,
-		"string-repeat":      []string{"hello", "world"},
-		"string-slice-multi": []string{"hello", "happy", "world"},
-		"string-map":         map[string]string{"hello": "world", "server": "name"},
-	}
+	t.Run("map and generic slice overridden by CLI", func(t *testing.T) {
+		defer cleanup()
+		os.Setenv("_get_opt_env_list", "a=1,b=2")
+		os.Setenv("_get_opt_env_list2", "1,2")
+		opt := getoptions.New()
+		m := opt.StringMap("m", 1, 99, opt.GetEnv("_get_opt_env_list"))
+		ii := getoptions.SliceOption(opt, "ii", 1, 99, strconv.Atoi, opt.GetEnv("_get_opt_env_list2"))
+		_, err := opt.Parse([]string{"-m", "c=3", "--ii", "3"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(m, map[string]string{"c": "3"}) {
+			t.Errorf("Unexpected value: %#v", m)
+		}
+		if !reflect.DeepEqual(*ii, []int{3}) {
+			t.Errorf("Unexpected value: %#v", *ii)
+		}
+	})
 
-	for k := range expected {
-		if !reflect.DeepEqual(opt.Value(k), expected[k]) {
-			t.Errorf("Wrong value: %v != %v", opt.Value(k), expected[k])
+	t.Run("custom separator", func(t *testing.T) {
+		defer cleanup()
+		os.Setenv("_get_opt_env_list", "1:2..4")
+		opt := getoptions.New()
+		opt.SetEnvSeparator(':')
+		cmd := opt.NewCommand("cmd", "")
+		ii := cmd.IntSlice("ii", 1, 99, opt.GetEnv("_get_opt_env_list"))
+		_, err := opt.Parse([]string{"cmd"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
 		}
-	}
+		if !reflect.DeepEqual(*ii, []int{1, 2, 3, 4}) {
+			t.Errorf("Unexpected value: %#v", *ii)
+		}
+	})
+
+	t.Run("float64 and duration slices", func(t *testing.T) {
+		defer cleanup()
+		os.Setenv("_get_opt_env_list", "1,2")
+		os.Setenv("_get_opt_env_list2", "1s,2m")
+		defer os.Unsetenv("_get_opt_env_list2")
+		opt := getoptions.New()
+		ff := opt.Float64Slice("ff", 1, 99, opt.GetEnv("_get_opt_env_list"))
+		dd := opt.DurationSlice("dd", 1, 99, opt.GetEnv("_get_opt_env_list2"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(*ff, []float64{1, 2}) {
+			t.Errorf("Unexpected value: %#v", *ff)
+		}
+		if !reflect.DeepEqual(*dd, []time.Duration{time.Second, 2 * time.Minute}) {
+			t.Errorf("Unexpected value: %#v", *dd)
+		}
+	})
+
+	t.Run("map", func(t *testing.T) {
+		defer cleanup()
+		os.Setenv("_get_opt_env_list", `k=v,"K2=a,b"`)
+		opt := getoptions.New()
+		opt.SetMapKeysToLower()
+		m := opt.StringMap("m", 1, 99, opt.GetEnv("_get_opt_env_list"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(m, map[string]string{"k": "v", "k2": "a,b"}) {
+			t.Errorf("Unexpected value: %#v", m)
+		}
+	})
+
+	t.Run("increment", func(t *testing.T) {
+		defer cleanup()
+		os.Setenv("_get_opt_env_list", "3")
+		opt := getoptions.New()
+		v := opt.Increment("v", 0, opt.GetEnv("_get_opt_env_list"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if *v != 3 {
+			t.Errorf("Unexpected value: %d", *v)
+		}
+	})
+
+	t.Run("increment overridden by CLI", func(t *testing.T) {
+		defer cleanup()
+		os.Setenv("_get_opt_env_list", "3")
+		opt := getoptions.New()
+		v := opt.Increment("v", 0, opt.GetEnv("_get_opt_env_list"))
+		_, err := opt.Parse([]string{"-v"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if *v != 1 {
+			t.Errorf("Unexpected value: %d", *v)
+		}
+	})
+
+	t.Run("bool error", func(t *testing.T) {
+		defer cleanup()
+		os.Setenv("_get_opt_env_list", "yes")
+		opt := getoptions.New()
+		b := opt.Bool("flag", false, opt.GetEnv("_get_opt_env_list"))
+		_, err := opt.Parse([]string{})
+		if err == nil || !errors.Is(err, getoptions.ErrorParsing) {
+			t.Errorf("Expected parsing error: %v", err)
+		}
+		expected := getoptions.ErrorParsing.Error() +
+			fmt.Sprintf(text.ErrorEnvVar, "_get_opt_env_list", fmt.Sprintf(text.ErrorConvertToBool, "flag", "yes"))
+		if err != nil && err.Error() != expected {
+			t.Errorf("Error string didn't match expected value:\n%s\n%s", err.Error(), expected)
+		}
+		if *b {
+			t.Errorf("Unexpected value: %v", *b)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		defer cleanup()
+		setups := []func(opt *getoptions.GetOpt){
+			func(opt *getoptions.GetOpt) { opt.IntSlice("o", 1, 99, opt.GetEnv("_get_opt_env_list")) },
+			func(opt *getoptions.GetOpt) { opt.StringMap("o", 1, 99, opt.GetEnv("_get_opt_env_list")) },
+			func(opt *getoptions.GetOpt) { opt.Increment("o", 0, opt.GetEnv("_get_opt_env_list")) },
+			func(opt *getoptions.GetOpt) { opt.StringSlice("o", 1, 99, opt.GetEnv("_get_opt_env_list")) },
+		}
+		os.Setenv("_get_opt_env_list", `x,"y`)
+		for _, setup := range setups {
+			opt := getoptions.New()
+			setup(opt)
+			_, err := opt.Parse([]string{})
+			if err == nil || !errors.Is(err, getoptions.ErrorParsing) {
+				t.Errorf("Expected parsing error: %v", err)
+			}
+			if opt.Called("o") {
+				t.Errorf("Option marked as called")
+			}
+		}
+	})
+
+	t.Run("multiple lines", func(t *testing.T) {
+		defer cleanup()
+		os.Setenv("_get_opt_env_list", "a,b\nc")
+		opt := getoptions.New()
+		ss := opt.StringSlice("ss", 1, 99, opt.GetEnv("_get_opt_env_list"))
+		_, err := opt.Parse([]string{})
+		expected := fmt.Sprintf(text.ErrorEnvVar, "_get_opt_env_list", fmt.Sprintf(text.ErrorEnvVarMultipleLines, ","))
+		if err == nil || !errors.Is(err, getoptions.ErrorParsing) || err.Error() != expected {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if len(*ss) != 0 {
+			t.Errorf("Unexpected value: %#v", *ss)
+		}
+
+		os.Setenv("_get_opt_env_list", "\"a\nb\",c")
+		opt = getoptions.New()
+		ss = opt.StringSlice("ss", 1, 99, opt.GetEnv("_get_opt_env_list"))
+		_, err = opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(*ss, []string{"a\nb", "c"}) {
+			t.Errorf("Unexpected value: %#v", *ss)
+		}
+	})
+
+	t.Run("error overridden by CLI", func(t *testing.T) {
+		defer cleanup()
+		os.Setenv("_get_opt_env_list", "x")
+		opt := getoptions.New()
+		i := opt.Int("i", 0, opt.GetEnv("_get_opt_env_list"))
+		_, err := opt.Parse([]string{"--i", "1"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if *i != 1 {
+			t.Errorf("Unexpected value: %d", *i)
+		}
+	})
+}
+
+func TestEnvPrefix(t *testing.T) {
+	cleanup := func() {
+		os.Unsetenv("MYTOOL_DRY_RUN")
+		os.Unsetenv("MYTOOL_DEPLOY_REGION")
+		os.Unsetenv("MYTOOL_DEPLOY_SKIP")
+		os.Unsetenv("_get_opt_env_explicit")
+	}
+
+	t.Run("bind options and command options", func(t *testing.T) {
+		defer cleanup()
+		os.Setenv("MYTOOL_DRY_RUN", "true")
+		os.Setenv("MYTOOL_DEPLOY_REGION", "us-west-2")
+		os.Setenv("MYTOOL_DEPLOY_SKIP", "x")
+		os.Setenv("_get_opt_env_explicit", "explicit")
+		opt := getoptions.New()
+		opt.SetEnvPrefix("mytool")
+		dryRun := opt.Bool("dry-run", false)
+		explicit := opt.String("name", "", opt.GetEnv("_get_opt_env_explicit"))
+		deploy := opt.NewCommand("deploy", "")
+		region := deploy.String("region", "us-east-1")
+		skip := deploy.String("skip", "", deploy.NoEnv())
+		opt.HelpCommand("help")
+		_, err := opt.Parse([]string{"deploy"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !*dryRun || opt.CalledAs("dry-run") != "MYTOOL_DRY_RUN" {
+			t.Errorf("Unexpected value: %v, %s", *dryRun, opt.CalledAs("dry-run"))
+		}
+		if *explicit != "explicit" {
+			t.Errorf("Unexpected value: %s", *explicit)
+		}
+		if *region != "us-west-2" || deploy.CalledAs("region") != "MYTOOL_DEPLOY_REGION" {
+			t.Errorf("Unexpected value: %s, %s", *region, deploy.CalledAs("region"))
+		}
+		if *skip != "" || deploy.Called("skip") {
+			t.Errorf("Unexpected value: %s", *skip)
+		}
+	})
+
+	t.Run("help lists derived names", func(t *testing.T) {
+		defer cleanup()
+		opt := getoptions.New()
+		opt.SetEnvPrefix("MYTOOL")
+		opt.Bool("dry-run", false)
+		deploy := opt.NewCommand("deploy", "")
+		deploy.String("region", "us-east-1")
+		opt.HelpCommand("help")
+		_, err := opt.Parse([]string{"deploy"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		expected := `NAME:
+    go-getoptions.test deploy
+
+SYNOPSIS:
+    go-getoptions.test deploy [--dry-run] [--help] [--region <string>] [<args>]
+
+OPTIONS:
+    --dry-run            (default: false, env: MYTOOL_DRY_RUN)
+
+    --help               (default: false)
+
+    --region <string>    (default: "us-east-1", env: MYTOOL_DEPLOY_REGION)
+
+`
+		if opt.Help() != expected {
+			t.Errorf("Unexpected help:\n%s", firstDiff(opt.Help(), expected))
+		}
+	})
+
+	t.Run("help environment section", func(t *testing.T) {
+		defer cleanup()
+		opt := getoptions.New()
+		opt.SetEnvPrefix("MYTOOL")
+		opt.Bool("dry-run", false)
+		opt.String("token", "", opt.NoEnv())
+		opt.String("region", "", opt.GetEnv("AWS_REGION"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		expected := `ENVIRONMENT:
+    AWS_REGION        --region <string>
+    MYTOOL_DRY_RUN    --dry-run
+
+`
+		got := opt.Help(getoptions.HelpEnvironment)
+		if got != expected {
+			t.Errorf("Unexpected help:\n%s", firstDiff(got, expected))
+		}
+	})
+}
+
+func TestConfigFile(t *testing.T) {
+	writeConfig := func(t *testing.T, content string) string {
+		t.Helper()
+		filename := filepath.Join(t.TempDir(), "config.json")
+		err := os.WriteFile(filename, []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+	cleanup := func() {
+		os.Unsetenv("_get_opt_env_config")
+	}
+
+	setup := func(filename string) (*getoptions.GetOpt, *getoptions.GetOpt) {
+		opt := getoptions.New()
+		opt.String("profile", "default", opt.GetEnv("_get_opt_env_config"))
+		opt.Int("port", 0, opt.Alias("p"))
+		opt.Bool("debug", false)
+		opt.StringSlice("tag", 1, 99)
+		opt.StringMap("label", 1, 99)
+		opt.Increment("v", 0)
+		opt.ConfigFile(filename, json.Unmarshal)
+		cmd := opt.NewCommand("cmd", "")
+		cmd.String("level", "info")
+		other := opt.NewCommand("other", "")
+		other.String("level", "warn")
+		return opt, cmd
+	}
+
+	t.Run("values", func(t *testing.T) {
+		filename := writeConfig(t, `{"profile": "dev", "p": 8080, "debug": true, "tag": ["a", "b"], "label": {"k": "v"}, "v": 3}`)
+		opt, _ := setup(filename)
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if opt.Value("profile") != "dev" || opt.Value("port") != 8080 || opt.Value("debug") != true || opt.Value("v") != 3 {
+			t.Errorf("Wrong values: %v %v %v %v", opt.Value("profile"), opt.Value("port"), opt.Value("debug"), opt.Value("v"))
+		}
+		if !reflect.DeepEqual(opt.Value("tag"), []string{"a", "b"}) || !reflect.DeepEqual(opt.Value("label"), map[string]string{"k": "v"}) {
+			t.Errorf("Wrong values: %v %v", opt.Value("tag"), opt.Value("label"))
+		}
+		if !opt.Called("profile") || opt.CalledAs("profile") != "config:"+filename {
+			t.Errorf("Wrong called as: %v", opt.CalledAs("profile"))
+		}
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		defer cleanup()
+		filename := writeConfig(t, `{"profile": "dev", "port": 8080}`)
+		os.Setenv("_get_opt_env_config", "staging")
+		opt, _ := setup(filename)
+		_, err := opt.Parse([]string{"--port", "9090"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if opt.Value("profile") != "staging" || opt.CalledAs("profile") != "_get_opt_env_config" {
+			t.Errorf("Wrong value: %v", opt.Value("profile"))
+		}
+		if opt.Value("port") != 9090 || opt.CalledAs("port") != "port" {
+			t.Errorf("Wrong value: %v", opt.Value("port"))
+		}
+	})
+
+	t.Run("command section", func(t *testing.T) {
+		filename := writeConfig(t, `{"port": 1, "cmd": {"level": "debug", "port": 2}, "other": {"level": "error"}}`)
+		opt, cmd := setup(filename)
+		called := false
+		cmd.SetCommandFn(func(ctx context.Context, opt *getoptions.GetOpt, args []string) error {
+			called = true
+			if opt.Value("level") != "debug" || opt.CalledAs("level") != "config:"+filename {
+				t.Errorf("Wrong value: %v", opt.Value("level"))
+			}
+			// The command section is more specific than the top level
+			if opt.Value("port") != 2 {
+				t.Errorf("Wrong value: %v", opt.Value("port"))
+			}
+			return nil
+		})
+		remaining, err := opt.Parse([]string{"cmd"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		err = opt.Dispatch(context.Background(), remaining)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !called {
+			t.Errorf("CommandFn not called")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		opt, _ := setup(filepath.Join(t.TempDir(), "missing.json"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("yaml shaped sections", func(t *testing.T) {
+		// YAML v2 libraries decode nested maps with interface{} keys.
+		yamlUnmarshal := func(data []byte, v interface{}) error {
+			m := v.(*map[string]interface{})
+			(*m)["profile"] = "dev"
+			(*m)["label"] = map[interface{}]interface{}{"k": "v", 1: true}
+			(*m)["cmd"] = map[interface{}]interface{}{"level": "debug"}
+			(*m)["other"] = map[interface{}]interface{}{"level": "error"}
+			return nil
+		}
+		filename := writeConfig(t, `profile: dev`)
+		opt := getoptions.New()
+		opt.String("profile", "default")
+		opt.StringMap("label", 1, 99)
+		cmd := opt.NewCommand("cmd", "")
+		cmd.String("level", "info")
+		// Defined from a command, the file is still read from the root.
+		cmd.ConfigFile(filename, yamlUnmarshal)
+		other := opt.NewCommand("other", "")
+		other.String("level", "warn")
+		_, err := opt.Parse([]string{"cmd"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if opt.Value("profile") != "dev" || !reflect.DeepEqual(opt.Value("label"), map[string]string{"k": "v", "1": "true"}) {
+			t.Errorf("Wrong values: %v %v", opt.Value("profile"), opt.Value("label"))
+		}
+		if cmd.Value("level") != "debug" || other.Value("level") != "warn" {
+			t.Errorf("Wrong values: %v %v", cmd.Value("level"), other.Value("level"))
+		}
+	})
+
+	t.Run("config option in command", func(t *testing.T) {
+		filename := writeConfig(t, `{"port": 8080}`)
+		opt := getoptions.New()
+		opt.Int("port", 0)
+		cmd := opt.NewCommand("cmd", "")
+		cmd.ConfigFileOption("config", "", json.Unmarshal)
+		_, err := opt.Parse([]string{"cmd", "--config", filename})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if opt.Value("port") != 8080 {
+			t.Errorf("Wrong value: %v", opt.Value("port"))
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, content := range []string{`{"unknown": 1}`, `{"port": "x"}`, `not json`, `{"v": "x"}`, `{"cmd": {"unknown": 1}}`, `{"cmd": "x"}`} {
+			filename := writeConfig(t, content)
+			opt, _ := setup(filename)
+			_, err := opt.Parse([]string{"cmd"})
+			if err == nil || !errors.Is(err, getoptions.ErrorParsing) {
+				t.Errorf("Expected parsing error for %s, got: %v", content, err)
+			}
+		}
+	})
+
+	t.Run("config option", func(t *testing.T) {
+		filename := writeConfig(t, `{"port": 8080}`)
+		opt := getoptions.New()
+		opt.Int("port", 0)
+		opt.ConfigFileOption("config", "", json.Unmarshal)
+		_, err := opt.Parse([]string{"--config", filename})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if opt.Value("port") != 8080 {
+			t.Errorf("Wrong value: %v", opt.Value("port"))
+		}
+
+		opt = getoptions.New()
+		opt.ConfigFileOption("config", "", json.Unmarshal)
+		_, err = opt.Parse([]string{"--config", filepath.Join(t.TempDir(), "missing.json")})
+		if err == nil {
+			t.Errorf("Missing explicit config file didn't raise errors")
+		}
+
+		opt = getoptions.New()
+		opt.Int("port", 0)
+		opt.ConfigFileOption("config", "", json.Unmarshal)
+		_, err = opt.Parse([]string{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if opt.Value("port") != 0 {
+			t.Errorf("Wrong value: %v", opt.Value("port"))
+		}
+	})
+}
+
+func TestAll(t *testing.T) {
+	var flag bool
+	var str string
+	var integer int
+	opt := getoptions.New()
+	opt.Bool("flag", false)
+	opt.BoolVar(&flag, "varflag", false)
+	opt.Bool("non-used-flag", false)
+	opt.String("string", "")
+	opt.StringVar(&str, "stringVar", "")
+	opt.Int("int", 0)
+	opt.IntVar(&integer, "intVar", 0)
+	opt.StringSlice("string-repeat", 1, 1)
+	opt.StringSlice("string-slice-multi", 1, 3)
+	opt.StringMap("string-map", 1, 1)
+
+	remaining, err := opt.Parse([]string{
+		"hello",
+		"--flag",
+		"--varflag",
+		"happy",
+		"--string", "hello",
+		"--stringVar", "hello",
+		"--int", "123",
+		"--intVar", "123",
+		"--string-repeat", "hello", "--string-repeat", "world",
+		"--string-slice-multi", "hello", "happy", "--string-slice-multi", "world",
+		"--string-map", "hello=world", "--string-map", "server=name",
+		"world",
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(remaining, []string{"hello", "happy", "world"}) {
+		t.Errorf("remaining didn't have expected value: %v != %v", remaining, []string{"hello", "happy", "world"})
+	}
+
+	expected := map[string]interface{}{
+		"flag":               true,
+		"string":             "hello",
+		"int":                123,
+		"string-repeat":      []string{"hello", "world"},
+		"string-slice-multi": []string{"hello", "happy", "world"},
+		"string-map":         map[string]string{"hello": "world", "server": "name"},
+	}
+
+	for k := range expected {
+		if !reflect.DeepEqual(opt.Value(k), expected[k]) {
+			t.Errorf("Wrong value: %v != %v", opt.Value(k), expected[k])
+		}
+	}
 
 	if flag != true {
 		t.Errorf("flag didn't have expected value: %v != %v", flag, true)
@@ -4224,3 +5063,1080 @@ func TestSetValue(t *testing.T) {
 		}
 	})
 }
+
+func TestSource(t *testing.T) {
+	os.Setenv("_get_opt_env_source", "env-value")
+	defer os.Unsetenv("_get_opt_env_source")
+	filename := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(filename, []byte(`{"config": "config-value"}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opt := getoptions.New()
+	opt.String("cli", "", opt.Alias("c"))
+	opt.String("env", "", opt.GetEnv("_get_opt_env_source"))
+	opt.String("config", "")
+	opt.String("default", "")
+	opt.String("set", "")
+	opt.ConfigFile(filename, json.Unmarshal)
+	_, err = opt.Parse([]string{"-c", "cli-value"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	err = opt.SetValue("set", "set-value")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		expected getoptions.ValueSource
+		str      string
+	}{
+		{"cli", getoptions.ValueSource{Kind: getoptions.SourceCLI, Name: "c"}, "cli"},
+		{"env", getoptions.ValueSource{Kind: getoptions.SourceEnv, Name: "_get_opt_env_source"}, "env"},
+		{"config", getoptions.ValueSource{Kind: getoptions.SourceConfig, Name: filename}, "config"},
+		{"default", getoptions.ValueSource{Kind: getoptions.SourceDefault}, "default"},
+		{"set", getoptions.ValueSource{Kind: getoptions.SourceSetValue}, "set-value"},
+		{"undeclared", getoptions.ValueSource{Kind: getoptions.SourceDefault}, "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := opt.Source(tt.name)
+			if got != tt.expected {
+				t.Errorf("got %#v, expected %#v", got, tt.expected)
+			}
+			if got.Kind.String() != tt.str {
+				t.Errorf("got %s, expected %s", got.Kind, tt.str)
+			}
+		})
+	}
+}
+
+func TestManPage(t *testing.T) {
+	setup := func() (*getoptions.GetOpt, *getoptions.GetOpt) {
+		opt := getoptions.New()
+		opt.Self("mytool", "Deploys things")
+		opt.Bool("dry-run", false, opt.Description("Don't apply changes"))
+		opt.String("profile", "", opt.Required(), opt.Alias("p"), opt.GetEnv("MYTOOL_PROFILE"))
+		deploy := opt.NewCommand("deploy", "Deploy the app")
+		deploy.HelpSynopsisArg("<env>", "Environment name")
+		deploy.NewCommand("rollback", "")
+		opt.HelpCommand("help")
+		return opt, deploy
+	}
+
+	t.Run("page", func(t *testing.T) {
+		_, deploy := setup()
+		buf := new(bytes.Buffer)
+		err := deploy.ManPage(buf, 1)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		expected := `.TH "MYTOOL\-DEPLOY" "1"
+.SH NAME
+mytool\-deploy \- Deploy the app
+.SH SYNOPSIS
+.B mytool deploy
+\-\-profile|\-p <string> [\-\-dry\-run] [\-\-help] <command> <env>
+.SH COMMANDS
+.TP
+.B rollback
+.SH ARGUMENTS
+.TP
+.B <env>
+Environment name
+.SH REQUIRED PARAMETERS
+.TP
+.B \-\-profile|\-p <string>
+(env: MYTOOL_PROFILE)
+.SH OPTIONS
+.TP
+.B \-\-dry\-run
+Don't apply changes (default: false)
+.TP
+.B \-\-help
+(default: false)
+.SH ENVIRONMENT
+.TP
+.B MYTOOL_PROFILE
+\-\-profile|\-p <string>
+`
+		if buf.String() != expected {
+			t.Errorf("Unexpected man page:\n%s", firstDiff(buf.String(), expected))
+		}
+	})
+
+	t.Run("localized headers", func(t *testing.T) {
+		defer func(h string) { text.HelpOptionsHeader = h }(text.HelpOptionsHeader)
+		text.HelpOptionsHeader = "OPCIONES"
+		opt, _ := setup()
+		buf := new(bytes.Buffer)
+		_ = opt.ManPage(buf, 1)
+		if !strings.Contains(buf.String(), "\n.SH OPCIONES\n") {
+			t.Errorf("Unexpected man page:\n%s", buf.String())
+		}
+	})
+
+	t.Run("one page per command", func(t *testing.T) {
+		opt, _ := setup()
+		dir := t.TempDir()
+		err := opt.ManPages(dir, 1)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		for _, name := range []string{"mytool.1", "mytool-deploy.1", "mytool-deploy-rollback.1"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+				t.Errorf("Missing man page: %s", err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, "mytool-help.1")); err == nil {
+			t.Errorf("Unexpected help man page")
+		}
+	})
+}
+
+func TestReferenceDoc(t *testing.T) {
+	setup := func() *getoptions.GetOpt {
+		opt := getoptions.New()
+		opt.Self("mytool", "Deploys things")
+		opt.String("format", "json", opt.ValidValues("json", "yaml"), opt.GetEnv("MYTOOL_FORMAT"))
+		deploy := opt.NewCommand("deploy", "Deploy the app")
+		deploy.String("profile", "", deploy.Required(), deploy.Description("AWS profile"))
+		deploy.HelpSynopsisArg("<env>", "Environment name")
+		opt.HelpCommand("help")
+		return opt
+	}
+
+	t.Run("markdown", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		err := setup().ReferenceDoc(buf, getoptions.DocMarkdown)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		expected := "# mytool\n\nDeploys things\n\n## SYNOPSIS\n\n```\nmytool [--format <string>] [--help] <command> [<args>]\n```\n\n" +
+			"## COMMANDS\n\n| Command | Description |\n| --- | --- |\n| `deploy` | Deploy the app |\n\n" +
+			"## OPTIONS\n\n| Option | Description | Default | Valid values | Environment |\n| --- | --- | --- | --- | --- |\n" +
+			"| `--format <string>` |  | `\"json\"` | `json`, `yaml` | `MYTOOL_FORMAT` |\n" +
+			"| `--help` |  | `false` |  |  |\n\n" +
+			"### mytool deploy\n\nDeploy the app\n\n#### SYNOPSIS\n\n```\nmytool deploy --profile <string> [--format <string>] [--help] <env>\n```\n\n" +
+			"#### ARGUMENTS\n\n| Argument | Description |\n| --- | --- |\n| `<env>` | Environment name |\n\n" +
+			"#### REQUIRED PARAMETERS\n\n| Option | Description | Default | Valid values | Environment |\n| --- | --- | --- | --- | --- |\n" +
+			"| `--profile <string>` | AWS profile |  |  |  |\n\n" +
+			"#### OPTIONS\n\n| Option | Description | Default | Valid values | Environment |\n| --- | --- | --- | --- | --- |\n" +
+			"| `--format <string>` |  | `\"json\"` | `json`, `yaml` | `MYTOOL_FORMAT` |\n" +
+			"| `--help` |  | `false` |  |  |\n\n"
+		if buf.String() != expected {
+			t.Errorf("Unexpected doc:\n%s", firstDiff(buf.String(), expected))
+		}
+	})
+
+	t.Run("asciidoc", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		err := setup().ReferenceDoc(buf, getoptions.DocAsciiDoc)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		for _, s := range []string{
+			"= mytool\n\nDeploys things\n\n== SYNOPSIS\n\n----\nmytool [--format <string>] [--help] <command> [<args>]\n----\n\n",
+			"=== mytool deploy\n",
+			"|`+--format <string>+`\n|\n|`+\"json\"+`\n|`+json+`, `+yaml+`\n|`+MYTOOL_FORMAT+`\n",
+		} {
+			if !strings.Contains(buf.String(), s) {
+				t.Errorf("Missing %q in doc:\n%s", s, buf.String())
+			}
+		}
+	})
+
+	t.Run("nested commands", func(t *testing.T) {
+		opt := getoptions.New()
+		opt.Self("a", "")
+		opt.NewCommand("b", "").NewCommand("c", "").NewCommand("d", "")
+		buf := new(bytes.Buffer)
+		err := opt.ReferenceDoc(buf, getoptions.DocMarkdown)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		for _, s := range []string{"\n# a\n", "\n## SYNOPSIS\n", "\n### a b\n", "\n#### SYNOPSIS\n", "\n##### a b c\n", "\n###### SYNOPSIS\n", "\n###### a b c d\n"} {
+			if !strings.Contains("\n"+buf.String(), s) {
+				t.Errorf("Missing %q in doc:\n%s", s, buf.String())
+			}
+		}
+		if strings.Contains(buf.String(), "#######") {
+			t.Errorf("Heading deeper than 6 levels in doc:\n%s", buf.String())
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		err := setup().ReferenceDoc(new(bytes.Buffer), getoptions.DocFormat(99))
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorUnknownDocFormat, 99) {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func TestSchema(t *testing.T) {
+	opt := getoptions.New()
+	opt.Self("mytool", "Deploys things")
+	opt.Bool("dry-run", false, opt.Alias("n"))
+	opt.String("format", "json", opt.ValidValues("json", "yaml"), opt.GetEnv("MYTOOL_FORMAT"))
+	deploy := opt.NewCommand("deploy", "Deploy the app")
+	deploy.StringSlice("tag", 1, 2, deploy.Required(), deploy.Description("Tags to apply"))
+	deploy.HelpSynopsisArg("<env>", "Environment name")
+	deploy.Args(getoptions.RangeArgs(1, 2))
+	opt.HelpCommand("help")
+
+	b, err := json.MarshalIndent(opt.Schema(), "", "  ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := `{
+  "name": "mytool",
+  "description": "Deploys things",
+  "options": [
+    {
+      "name": "dry-run",
+      "aliases": [
+        "n"
+      ],
+      "type": "bool",
+      "min_args": 0,
+      "max_args": 0,
+      "required": false,
+      "default": "false"
+    },
+    {
+      "name": "format",
+      "type": "string",
+      "arg_name": "string",
+      "min_args": 1,
+      "max_args": 1,
+      "required": false,
+      "valid_values": [
+        "json",
+        "yaml"
+      ],
+      "suggested_values": [
+        "json",
+        "yaml"
+      ],
+      "env_var": "MYTOOL_FORMAT",
+      "default": "\"json\""
+    },
+    {
+      "name": "help",
+      "type": "bool",
+      "min_args": 0,
+      "max_args": 0,
+      "required": false,
+      "default": "false"
+    }
+  ],
+  "commands": [
+    {
+      "name": "deploy",
+      "description": "Deploy the app",
+      "arguments": [
+        {
+          "name": "\u003cenv\u003e",
+          "description": "Environment name"
+        }
+      ],
+      "args": {
+        "min": 1,
+        "max": 2
+      },
+      "options": [
+        {
+          "name": "tag",
+          "type": "string-slice",
+          "arg_name": "string",
+          "description": "Tags to apply",
+          "min_args": 1,
+          "max_args": 2,
+          "required": true,
+          "default": "[]"
+        }
+      ]
+    }
+  ]
+}`
+	if string(b) != expected {
+		t.Errorf("Unexpected schema:\n%s", firstDiff(string(b), expected))
+	}
+
+	var s getoptions.Schema
+	err = json.Unmarshal(b, &s)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(&s, opt.Schema()) {
+		t.Errorf("Schema doesn't round trip: %#v", s)
+	}
+
+	t.Run("definition order", func(t *testing.T) {
+		// Parent options defined after the command aren't copied to it, the schema must be the same.
+		opt := getoptions.New()
+		opt.Self("mytool", "Deploys things")
+		deploy := opt.NewCommand("deploy", "Deploy the app")
+		deploy.StringSlice("tag", 1, 2, deploy.Required(), deploy.Description("Tags to apply"))
+		deploy.HelpSynopsisArg("<env>", "Environment name")
+		deploy.Args(getoptions.RangeArgs(1, 2))
+		opt.HelpCommand("help")
+		opt.Bool("dry-run", false, opt.Alias("n"))
+		opt.String("format", "json", opt.ValidValues("json", "yaml"), opt.GetEnv("MYTOOL_FORMAT"))
+		got, err := json.MarshalIndent(opt.Schema(), "", "  ")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if string(got) != expected {
+			t.Errorf("Unexpected schema:\n%s", firstDiff(string(got), expected))
+		}
+	})
+
+	t.Run("named args", func(t *testing.T) {
+		opt := getoptions.New()
+		opt.NamedArg("<src>", "")
+		opt.PositionalStringSlice(&[]string{}, "<dst>", "", 1, -1)
+		if args := opt.Schema().Args; args == nil || *args != getoptions.MinimumArgs(2) {
+			t.Errorf("Unexpected args: %v", args)
+		}
+		if args := getoptions.New().Schema().Args; args != nil {
+			t.Errorf("Unexpected args: %v", args)
+		}
+	})
+}
+
+func TestCompareSchemas(t *testing.T) {
+	setup := func() *getoptions.GetOpt {
+		opt := getoptions.New()
+		opt.Self("mytool", "")
+		opt.Bool("dry-run", false, opt.Alias("n"))
+		opt.String("format", "json", opt.ValidValues("json", "yaml", "table"))
+		opt.String("region", "")
+		opt.Int("retries", 3, opt.Alias("r"))
+		deploy := opt.NewCommand("deploy", "")
+		deploy.String("profile", "")
+		deploy.Args(getoptions.RangeArgs(0, 2))
+		opt.NewCommand("destroy", "")
+		return opt
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		changes := getoptions.CompareSchemas(setup().Schema(), setup().Schema())
+		if len(changes) != 0 {
+			t.Errorf("Unexpected changes: %v", changes)
+		}
+	})
+
+	t.Run("compatible changes", func(t *testing.T) {
+		opt := getoptions.New()
+		opt.Self("mytool", "")
+		opt.Bool("dry-run", false, opt.Alias("n", "noop"))
+		opt.String("format", "json", opt.ValidValues("json", "yaml", "table", "csv"))
+		opt.String("aws-region", "", opt.Alias("region"))
+		opt.Int("retries", 3, opt.Alias("r"))
+		opt.String("new", "")
+		deploy := opt.NewCommand("deploy", "")
+		deploy.String("profile", "")
+		deploy.Args(getoptions.MinimumArgs(0))
+		opt.NewCommand("destroy", "")
+		opt.NewCommand("plan", "")
+		changes := getoptions.CompareSchemas(setup().Schema(), opt.Schema())
+		if len(changes) != 0 {
+			t.Errorf("Unexpected changes: %v", changes)
+		}
+	})
+
+	t.Run("breaking changes", func(t *testing.T) {
+		opt := getoptions.New()
+		opt.Self("mytool", "")
+		opt.Bool("dry-run", false)
+		opt.String("format", "json", opt.ValidValues("json", "yaml"))
+		opt.String("region", "", opt.ValidValues("us-east-1"), opt.Alias("r"))
+		opt.String("retries", "3")
+		opt.String("token", "", opt.Required())
+		opt.Args(getoptions.MaximumArgs(3))
+		deploy := opt.NewCommand("deploy", "")
+		deploy.String("profile", "", deploy.Required())
+		deploy.Args(getoptions.ExactArgs(1))
+		changes := getoptions.CompareSchemas(setup().Schema(), opt.Schema())
+		got := []string{}
+		for _, change := range changes {
+			got = append(got, change.String())
+		}
+		expected := []string{
+			"mytool: positional arguments limited to a maximum of 3",
+			"mytool --dry-run: alias 'n' removed",
+			"mytool --format: valid value 'table' removed",
+			`mytool --region: valid values restricted to ["us-east-1"]`,
+			"mytool --retries: alias 'r' moved to option 'region'",
+			"mytool --retries: type changed from 'int' to 'string'",
+			"mytool --token: new required option",
+			"mytool deploy: minimum positional arguments raised from 0 to 1",
+			"mytool deploy: maximum positional arguments lowered from 2 to 1",
+			"mytool deploy --profile: option is now required",
+			"mytool: command 'destroy' removed",
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Unexpected changes:\n%s", strings.Join(got, "\n"))
+		}
+	})
+
+	t.Run("removed option", func(t *testing.T) {
+		opt := getoptions.New()
+		opt.Self("mytool", "")
+		changes := getoptions.CompareSchemas(setup().Schema(), opt.Schema())
+		if len(changes) != 6 || changes[0].Option != "dry-run" || changes[0].Message != "option removed" {
+			t.Errorf("Unexpected changes: %v", changes)
+		}
+	})
+}
+
+func TestParseErrorTypes(t *testing.T) {
+	setup := func() *getoptions.GetOpt {
+		opt := getoptions.New()
+		opt.String("profile", "", opt.Alias("p"), opt.ValidValues("dev", "prod"))
+		opt.String("proxy", "")
+		opt.Int("port", 0, opt.Alias("P"))
+		opt.IntSlice("ids", 1, 1)
+		return opt
+	}
+
+	t.Run("unknown option", func(t *testing.T) {
+		_, err := setup().Parse([]string{"--unknown"})
+		var e *getoptions.UnknownOptionError
+		if !errors.As(err, &e) || !errors.Is(err, getoptions.ErrorParsing) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if e.Option != "unknown" || e.UsedAlias != "unknown" || e.Value != "--unknown" || err.Error() != fmt.Sprintf(text.MessageOnUnknown, "unknown") {
+			t.Errorf("Unexpected error: %#v, %s", e, err)
+		}
+
+		_, err = setup().Parse([]string{"--unknown=value"})
+		if !errors.As(err, &e) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if e.Option != "unknown" || e.Value != "--unknown=value" {
+			t.Errorf("Unexpected error: %#v, %s", e, err)
+		}
+	})
+
+	t.Run("ambiguous option", func(t *testing.T) {
+		_, err := setup().Parse([]string{"--pr"})
+		var e *getoptions.AmbiguousOptionError
+		if !errors.As(err, &e) || !errors.Is(err, getoptions.ErrorParsing) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if e.Option != "pr" || e.Value != "--pr" || !reflect.DeepEqual(e.Candidates, []string{"profile", "proxy"}) {
+			t.Errorf("Unexpected error: %#v", e)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorAmbiguousArgument, "--pr", []string{"profile", "proxy"}) {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("missing argument", func(t *testing.T) {
+		_, err := setup().Parse([]string{"-P"})
+		var e *getoptions.MissingArgumentError
+		if !errors.As(err, &e) || !errors.Is(err, getoptions.ErrorParsing) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if e.Option != "port" || e.UsedAlias != "P" || e.Value != "" || err.Error() != fmt.Sprintf(text.ErrorMissingArgument, "P") {
+			t.Errorf("Unexpected error: %#v, %s", e, err)
+		}
+
+		_, err = setup().Parse([]string{"--port", "-x"})
+		if !errors.As(err, &e) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if e.Option != "port" || e.Value != "-x" || err.Error() != fmt.Sprintf(text.ErrorArgumentWithDash, "port") {
+			t.Errorf("Unexpected error: %#v, %s", e, err)
+		}
+	})
+
+	t.Run("conversion", func(t *testing.T) {
+		_, err := setup().Parse([]string{"-P", "abc"})
+		var e *getoptions.ConversionError
+		if !errors.As(err, &e) || !errors.Is(err, getoptions.ErrorParsing) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if e.Option != "port" || e.UsedAlias != "P" || e.Value != "abc" || e.Type != "int" || err.Error() != fmt.Sprintf(text.ErrorConvertToInt, "P", "abc") {
+			t.Errorf("Unexpected error: %#v, %s", e, err)
+		}
+		if !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("Underlying error not wrapped: %#v", e.Err)
+		}
+
+		_, err = setup().Parse([]string{"--ids", "x"})
+		if !errors.As(err, &e) || e.Option != "ids" || e.Value != "x" || e.Type != "int-slice" {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+
+		opt := getoptions.New()
+		_, _, err = opt.GetRequiredArgInt([]string{"x"})
+		if !errors.As(err, &e) || e.Option != "" || e.Value != "x" || e.Type != "int" || err.Error() != fmt.Sprintf(text.ErrorConvertArgumentToInt, "x") {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("env conversion", func(t *testing.T) {
+		os.Setenv("_getoptions_port", "abc")
+		defer os.Unsetenv("_getoptions_port")
+		opt := getoptions.New()
+		opt.Int("port", 0, opt.GetEnv("_getoptions_port"))
+		_, err := opt.Parse([]string{})
+		var e *getoptions.ConversionError
+		if !errors.As(err, &e) || !errors.Is(err, getoptions.ErrorParsing) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if e.Option != "port" || e.Value != "abc" {
+			t.Errorf("Unexpected error: %#v", e)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorEnvVar, "_getoptions_port", fmt.Sprintf(text.ErrorConvertToInt, "port", "abc")) {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := setup().Parse([]string{"-p", "qa"})
+		var e *getoptions.InvalidValueError
+		if !errors.As(err, &e) || !errors.Is(err, getoptions.ErrorParsing) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if e.Option != "profile" || e.UsedAlias != "p" || e.Value != "qa" || !reflect.DeepEqual(e.ValidValues, []string{"dev", "prod"}) {
+			t.Errorf("Unexpected error: %#v", e)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorInvalidValue, "profile", []string{"dev", "prod"}) {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+}
+
+func TestDidYouMean(t *testing.T) {
+	setup := func() *getoptions.GetOpt {
+		opt := getoptions.New()
+		opt.SetDidYouMean(true)
+		opt.Bool("verbose", false, opt.Alias("v"))
+		opt.String("profile", "")
+		opt.NewCommand("log", "")
+		opt.NewCommand("show", "")
+		opt.HelpCommand("help")
+		return opt
+	}
+
+	t.Run("unknown option", func(t *testing.T) {
+		_, err := setup().Parse([]string{"--vrebose"})
+		var e *getoptions.UnknownOptionError
+		if !errors.As(err, &e) || !reflect.DeepEqual(e.Suggestions, []string{"verbose"}) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if err.Error() != "Unknown option 'vrebose', did you mean '--verbose'?" {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("unknown option without suggestions", func(t *testing.T) {
+		_, err := setup().Parse([]string{"--xyz"})
+		if err == nil || err.Error() != fmt.Sprintf(text.MessageOnUnknown, "xyz") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("unknown option warning", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		getoptions.Writer = buf
+		defer func() { getoptions.Writer = os.Stderr }()
+		opt := setup()
+		opt.SetUnknownMode(getoptions.Warn)
+		_, err := opt.Parse([]string{"--proflie"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if buf.String() != "WARNING: Unknown option 'proflie', did you mean '--profile'?\n" {
+			t.Errorf("Unexpected output: %s", buf.String())
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		opt := setup()
+		opt.SetDidYouMean(false)
+		_, err := opt.Parse([]string{"--vrebose"})
+		if err == nil || err.Error() != fmt.Sprintf(text.MessageOnUnknown, "vrebose") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		opt = setup()
+		opt.SetDidYouMean(false)
+		remaining, err := opt.Parse([]string{"lgo"})
+		if err != nil || !reflect.DeepEqual(remaining, []string{"lgo"}) {
+			t.Errorf("Unexpected result: %v, %v", remaining, err)
+		}
+	})
+
+	t.Run("mistyped command", func(t *testing.T) {
+		_, err := setup().Parse([]string{"lgo"})
+		var e *getoptions.UnknownCommandError
+		if !errors.As(err, &e) || !errors.Is(err, getoptions.ErrorParsing) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if e.Command != "lgo" || !reflect.DeepEqual(e.Suggestions, []string{"log"}) {
+			t.Errorf("Unexpected error: %#v", e)
+		}
+		if err.Error() != "Unknown command 'lgo', did you mean 'log'?" {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("unrelated argument", func(t *testing.T) {
+		remaining, err := setup().Parse([]string{"file.txt"})
+		if err != nil || !reflect.DeepEqual(remaining, []string{"file.txt"}) {
+			t.Errorf("Unexpected result: %v, %v", remaining, err)
+		}
+	})
+
+	t.Run("command argument", func(t *testing.T) {
+		remaining, err := setup().Parse([]string{"log", "shwo"})
+		if err != nil || !reflect.DeepEqual(remaining, []string{"shwo"}) {
+			t.Errorf("Unexpected result: %v, %v", remaining, err)
+		}
+	})
+
+	t.Run("help command is not a candidate", func(t *testing.T) {
+		for _, arg := range []string{"hello", "he"} {
+			opt := setup()
+			remaining, err := opt.Parse([]string{"log", arg})
+			if err != nil || !reflect.DeepEqual(remaining, []string{arg}) {
+				t.Errorf("Unexpected result for leaf command: %v, %v", remaining, err)
+			}
+
+			opt = getoptions.New()
+			opt.SetDidYouMean(true)
+			opt.HelpCommand("help")
+			remaining, err = opt.Parse([]string{arg})
+			if err != nil || !reflect.DeepEqual(remaining, []string{arg}) {
+				t.Errorf("Unexpected result for root without commands: %v, %v", remaining, err)
+			}
+		}
+	})
+
+	t.Run("inherited by commands", func(t *testing.T) {
+		opt := getoptions.New()
+		opt.SetDidYouMean(true)
+		cmd := opt.NewCommand("deploy", "")
+		cmd.NewCommand("rollback", "")
+		_, err := opt.Parse([]string{"deploy", "rolback"})
+		var e *getoptions.UnknownCommandError
+		if !errors.As(err, &e) || !reflect.DeepEqual(e.Suggestions, []string{"rollback"}) {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+	})
+}
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(opt *getoptions.GetOpt)
+		args     []string
+		expected string
+	}{
+		{"exact ok", func(opt *getoptions.GetOpt) { opt.Args(getoptions.ExactArgs(2)) }, []string{"a", "b"}, ""},
+		{"exact few", func(opt *getoptions.GetOpt) { opt.Args(getoptions.ExactArgs(2)) }, []string{"a"}, fmt.Sprintf(text.ErrorExactArguments, 2, 1)},
+		{"exact many", func(opt *getoptions.GetOpt) { opt.Args(getoptions.ExactArgs(2)) }, []string{"a", "b", "c"}, fmt.Sprintf(text.ErrorExactArguments, 2, 3)},
+		{"range ok", func(opt *getoptions.GetOpt) { opt.Args(getoptions.RangeArgs(1, 3)) }, []string{"a", "b", "c"}, ""},
+		{"range few", func(opt *getoptions.GetOpt) { opt.Args(getoptions.RangeArgs(1, 3)) }, []string{}, fmt.Sprintf(text.ErrorNotEnoughArguments, 1, 0)},
+		{"range many", func(opt *getoptions.GetOpt) { opt.Args(getoptions.RangeArgs(1, 3)) }, []string{"a", "b", "c", "d"}, fmt.Sprintf(text.ErrorTooManyArguments, 3, 4)},
+		{"minimum", func(opt *getoptions.GetOpt) { opt.Args(getoptions.MinimumArgs(1)) }, []string{"a", "b", "c", "d"}, ""},
+		{"maximum", func(opt *getoptions.GetOpt) { opt.Args(getoptions.MaximumArgs(1)) }, []string{"a", "b"}, fmt.Sprintf(text.ErrorTooManyArguments, 1, 2)},
+		{"no args", func(opt *getoptions.GetOpt) { opt.Args(getoptions.NoArgs()) }, []string{"a"}, fmt.Sprintf(text.ErrorExactArguments, 0, 1)},
+		{"named missing", func(opt *getoptions.GetOpt) {
+			opt.NamedArg("<src>", "")
+			opt.NamedArg("<dst>", "")
+		}, []string{"a"}, fmt.Sprintf(text.ErrorMissingRequiredNamedArgument, "<dst>")},
+		{"named many", func(opt *getoptions.GetOpt) {
+			opt.NamedArg("<src>", "")
+		}, []string{"a", "b"}, fmt.Sprintf(text.ErrorExactArguments, 1, 2)},
+		{"named with spec", func(opt *getoptions.GetOpt) {
+			opt.NamedArg("<src>", "")
+			opt.Args(getoptions.MinimumArgs(1))
+		}, []string{"a", "b", "c"}, ""},
+		{"named with spec missing", func(opt *getoptions.GetOpt) {
+			opt.NamedArg("<src>", "")
+			opt.Args(getoptions.MinimumArgs(1))
+		}, []string{}, fmt.Sprintf(text.ErrorMissingRequiredNamedArgument, "<src>")},
+		{"help called", func(opt *getoptions.GetOpt) { opt.Args(getoptions.ExactArgs(2)) }, []string{"--help"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := getoptions.New()
+			opt.HelpCommand("help")
+			tt.setup(opt)
+			_, err := opt.Parse(tt.args)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				return
+			}
+			var e *getoptions.ArgsError
+			if !errors.As(err, &e) || !errors.Is(err, getoptions.ErrorParsing) {
+				t.Fatalf("Unexpected error: %#v", err)
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		})
+	}
+
+	t.Run("command", func(t *testing.T) {
+		opt := getoptions.New()
+		opt.Self("program", "")
+		cp := opt.NewCommand("cp", "")
+		cp.NamedArg("<src>", "Source file")
+		cp.NamedArg("<dst>", "Destination file")
+		_, err := opt.Parse([]string{"cp", "a"})
+		var e *getoptions.ArgsError
+		if !errors.As(err, &e) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if e.Command != "program cp" || e.Name != "<dst>" || e.Min != 2 || e.Max != 2 || !reflect.DeepEqual(e.Args, []string{"a"}) {
+			t.Errorf("Unexpected error: %#v", e)
+		}
+		expected := `NAME:
+    program cp
+
+SYNOPSIS:
+    program cp <src> <dst>
+
+ARGUMENTS:
+    <src>    Source file
+
+    <dst>    Destination file
+
+`
+		if cp.Help() != expected {
+			t.Errorf("Unexpected help:\n%s", firstDiff(cp.Help(), expected))
+		}
+	})
+}
+
+func TestPositionalArgs(t *testing.T) {
+	t.Run("bound values", func(t *testing.T) {
+		var name, tag string
+		var count int
+		var ratio float64
+		var ids []int
+		opt := getoptions.New()
+		opt.Bool("verbose", false)
+		opt.PositionalString(&name, "<name>", "")
+		opt.PositionalInt(&count, "<count>", "")
+		opt.PositionalFloat64(&ratio, "<ratio>", "")
+		opt.PositionalStringOptional(&tag, "<tag>", "")
+		opt.PositionalIntSlice(&ids, "<id>...", "", 0, -1)
+		remaining, err := opt.Parse([]string{"x", "3", "--verbose", "0.5", "latest", "1", "2", "3"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if name != "x" || count != 3 || ratio != 0.5 || tag != "latest" || !reflect.DeepEqual(ids, []int{1, 2, 3}) {
+			t.Errorf("Unexpected values: %v %v %v %v %v", name, count, ratio, tag, ids)
+		}
+		if !reflect.DeepEqual(remaining, []string{"x", "3", "0.5", "latest", "1", "2", "3"}) {
+			t.Errorf("Unexpected remaining: %v", remaining)
+		}
+	})
+
+	t.Run("optional values", func(t *testing.T) {
+		name, tag := "", "default"
+		var files []string
+		setup := func() *getoptions.GetOpt {
+			opt := getoptions.New()
+			opt.PositionalString(&name, "<name>", "")
+			opt.PositionalStringOptional(&tag, "<tag>", "")
+			opt.PositionalStringSlice(&files, "<file>...", "", 0, 2)
+			return opt
+		}
+		_, err := setup().Parse([]string{"x"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if name != "x" || tag != "default" || files != nil {
+			t.Errorf("Unexpected values: %v %v %v", name, tag, files)
+		}
+		_, err = setup().Parse([]string{"x", "y", "a", "b", "c"})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorTooManyArguments, 4, 5) {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("fixed size slice", func(t *testing.T) {
+		var pair []string
+		count := 7
+		setup := func() *getoptions.GetOpt {
+			opt := getoptions.New()
+			opt.PositionalStringSlice(&pair, "<a> <b>", "", 2, 2)
+			opt.PositionalIntOptional(&count, "<count>", "")
+			return opt
+		}
+		_, err := setup().Parse([]string{"a", "b"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(pair, []string{"a", "b"}) || count != 7 {
+			t.Errorf("Unexpected values: %v %v", pair, count)
+		}
+		_, err = setup().Parse([]string{"c", "d", "3"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(pair, []string{"c", "d"}) || count != 3 {
+			t.Errorf("Unexpected values: %v %v", pair, count)
+		}
+		_, err = setup().Parse([]string{"c", "d", "x"})
+		var e *getoptions.ConversionError
+		if !errors.As(err, &e) || e.Argument != "<count>" || e.Value != "x" {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		var name string
+		var files []string
+		opt := getoptions.New()
+		opt.PositionalString(&name, "<name>", "")
+		opt.PositionalStringSlice(&files, "<file>...", "", 1, -1)
+		_, err := opt.Parse([]string{"x"})
+		var e *getoptions.ArgsError
+		if !errors.As(err, &e) || e.Name != "<file>..." || e.Min != 2 || e.Max != -1 {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorMissingRequiredNamedArgument, "<file>...") {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("conversion", func(t *testing.T) {
+		var count int
+		var ids []int
+		setup := func() *getoptions.GetOpt {
+			opt := getoptions.New()
+			opt.PositionalInt(&count, "<count>", "")
+			opt.PositionalIntSlice(&ids, "<id>...", "", 0, -1)
+			return opt
+		}
+		_, err := setup().Parse([]string{"x"})
+		var e *getoptions.ConversionError
+		if !errors.As(err, &e) || !errors.Is(err, getoptions.ErrorParsing) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if e.Argument != "<count>" || e.Value != "x" || e.Type != "int" || err.Error() != fmt.Sprintf(text.ErrorConvertNamedArgumentToInt, "<count>", "x") {
+			t.Errorf("Unexpected error: %#v, %s", e, err)
+		}
+		_, err = setup().Parse([]string{"1", "2", "y"})
+		if !errors.As(err, &e) || e.Argument != "<id>..." || e.Value != "y" || e.Type != "int-slice" {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+
+		var ratio float64
+		opt := getoptions.New()
+		opt.PositionalFloat64Optional(&ratio, "<ratio>", "")
+		_, err = opt.Parse([]string{"z"})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorConvertNamedArgumentToFloat64, "<ratio>", "z") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		var src string
+		var dst []string
+		opt := getoptions.New()
+		opt.Self("cp", "")
+		opt.PositionalString(&src, "<src>", "Source file")
+		opt.PositionalStringSlice(&dst, "<dst>...", "Destinations", 1, -1)
+		expected := `SYNOPSIS:
+    cp <src> <dst>...
+
+ARGUMENTS:
+    <src>       Source file
+
+    <dst>...    Destinations
+
+`
+		if opt.Help() != expected {
+			t.Errorf("Unexpected help:\n%s", firstDiff(opt.Help(), expected))
+		}
+	})
+
+	t.Run("definition errors", func(t *testing.T) {
+		panics := func(name string, fn func(opt *getoptions.GetOpt)) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s: definition didn't panic", name)
+				}
+			}()
+			fn(getoptions.New())
+		}
+		var s string
+		var ss []string
+		panics("after variadic", func(opt *getoptions.GetOpt) {
+			opt.PositionalStringSlice(&ss, "<a>...", "", 0, -1)
+			opt.PositionalString(&s, "<b>", "")
+		})
+		panics("required after optional", func(opt *getoptions.GetOpt) {
+			opt.PositionalStringOptional(&s, "<a>", "")
+			opt.PositionalString(&s, "<b>", "")
+		})
+		panics("max smaller than min", func(opt *getoptions.GetOpt) {
+			opt.PositionalStringSlice(&ss, "<a>...", "", 2, 1)
+		})
+		panics("required after optional slice values", func(opt *getoptions.GetOpt) {
+			opt.PositionalStringSlice(&ss, "<a>...", "", 1, 2)
+			opt.PositionalString(&s, "<b>", "")
+		})
+	})
+}
+
+func TestMutuallyExclusive(t *testing.T) {
+	setup := func() *getoptions.GetOpt {
+		opt := getoptions.New()
+		opt.Self("program", "")
+		opt.HelpCommand("help")
+		opt.Bool("json", false, opt.Alias("j"))
+		opt.Bool("yaml", false, opt.Alias("y"), opt.GetEnv("_get_opt_env_yaml"))
+		opt.Bool("table", false)
+		opt.MutuallyExclusive("json", "yaml", "table")
+		opt.NewCommand("show", "")
+		return opt
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		options     []string
+		usedAliases []string
+	}{
+		{"none", []string{}, nil, nil},
+		{"one", []string{"--json"}, nil, nil},
+		{"same option twice", []string{"--json", "-j"}, nil, nil},
+		{"two", []string{"--yaml", "--json"}, []string{"json", "yaml"}, []string{"json", "yaml"}},
+		{"aliases", []string{"-j", "-y", "--table"}, []string{"json", "yaml", "table"}, []string{"j", "y", "table"}},
+		{"command", []string{"show", "--table", "-j"}, []string{"json", "table"}, []string{"j", "table"}},
+		{"help called", []string{"--json", "--yaml", "--help"}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := setup().Parse(tt.args)
+			if tt.options == nil {
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				return
+			}
+			var e *getoptions.MutuallyExclusiveError
+			if !errors.As(err, &e) || !errors.Is(err, getoptions.ErrorParsing) {
+				t.Fatalf("Unexpected error: %#v", err)
+			}
+			if !reflect.DeepEqual(e.Options, tt.options) || !reflect.DeepEqual(e.UsedAliases, tt.usedAliases) {
+				t.Errorf("Unexpected error: %#v", e)
+			}
+			if !reflect.DeepEqual(e.Group, []string{"json", "yaml", "table"}) {
+				t.Errorf("Unexpected group: %v", e.Group)
+			}
+		})
+	}
+
+	t.Run("message", func(t *testing.T) {
+		_, err := setup().Parse([]string{"--json", "-y"})
+		expected := fmt.Sprintf(text.ErrorMutuallyExclusive, "'--json', '-y'")
+		if err == nil || err.Error() != expected {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("env var overridden by CLI", func(t *testing.T) {
+		os.Setenv("_get_opt_env_yaml", "true")
+		defer os.Unsetenv("_get_opt_env_yaml")
+		opt := setup()
+		_, err := opt.Parse([]string{"--json"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !opt.Value("json").(bool) || opt.Value("yaml").(bool) || opt.Called("yaml") {
+			t.Errorf("Unexpected values: json %v, yaml %v", opt.Value("json"), opt.Value("yaml"))
+		}
+
+		opt = setup()
+		_, err = opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if opt.Value("json").(bool) || !opt.Value("yaml").(bool) {
+			t.Errorf("Unexpected values: json %v, yaml %v", opt.Value("json"), opt.Value("yaml"))
+		}
+	})
+
+	t.Run("env var that can't be reset", func(t *testing.T) {
+		os.Setenv("_get_opt_env_ip", "10.0.0.1")
+		defer os.Unsetenv("_get_opt_env_ip")
+		opt := getoptions.New()
+		opt.String("host", "")
+		opt.Var(&ipValue{}, "ip", opt.GetEnv("_get_opt_env_ip"))
+		opt.MutuallyExclusive("host", "ip")
+		_, err := opt.Parse([]string{"--host", "example.com"})
+		var e *getoptions.MutuallyExclusiveError
+		if !errors.As(err, &e) || !reflect.DeepEqual(e.UsedAliases, []string{"host", "_get_opt_env_ip"}) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorMutuallyExclusive, "'--host', '_get_opt_env_ip'") {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("config file overridden by CLI", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "config.json")
+		err := os.WriteFile(filename, []byte(`{"yaml": true}`), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opt := setup()
+		opt.ConfigFile(filename, json.Unmarshal)
+		_, err = opt.Parse([]string{"--table"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !opt.Value("table").(bool) || opt.Value("yaml").(bool) {
+			t.Errorf("Unexpected values: table %v, yaml %v", opt.Value("table"), opt.Value("yaml"))
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		expected := `SYNOPSIS:
+    program [--help] (--json|-j|--yaml|-y|--table) <command> [<args>]
+
+`
+		got := setup().Help(getoptions.HelpSynopsis)
+		if got != expected {
+			t.Errorf("Unexpected help:\n%s", firstDiff(got, expected))
+		}
+	})
+
+	t.Run("definition errors", func(t *testing.T) {
+		panics := func(name string, fn func(opt *getoptions.GetOpt)) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s: definition didn't panic", name)
+				}
+			}()
+			opt := getoptions.New()
+			opt.Bool("json", false)
+			opt.Bool("yaml", false)
+			fn(opt)
+		}
+		panics("single option", func(opt *getoptions.GetOpt) { opt.MutuallyExclusive("json") })
+		panics("undefined option", func(opt *getoptions.GetOpt) { opt.MutuallyExclusive("json", "xml") })
+		panics("already in a group", func(opt *getoptions.GetOpt) {
+			opt.Bool("table", false)
+			opt.MutuallyExclusive("json", "yaml")
+			opt.MutuallyExclusive("yaml", "table")
+		})
+	})
+}
@@ -0,0 +1,249 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2025  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+
+	"github.com/DavidGamba/go-getoptions/internal/option"
+)
+
+// Schema - Serializable description of a command, its options and its child commands.
+type Schema struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Arguments   []SchemaArg    `json:"arguments,omitempty"`
+	Args        *ArgsSpec      `json:"args,omitempty"` // Number of positional arguments accepted, nil when not restricted
+	Options     []SchemaOption `json:"options,omitempty"`
+	Commands    []*Schema      `json:"commands,omitempty"`
+}
+
+// SchemaArg - Serializable description of a positional argument defined with `opt.HelpSynopsisArg`.
+type SchemaArg struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// SchemaOption - Serializable description of an option.
+//
+// Type is one of: bool, increment, string, int, float64, duration, value,
+// their -optional variants (string-optional, etc.), their -slice variants
+// (string-slice, etc.) and string-map.
+//
+// Default is the default value as shown in the help.
+type SchemaOption struct {
+	Name            string   `json:"name"`
+	Aliases         []string `json:"aliases,omitempty"`
+	Type            string   `json:"type"`
+	ArgName         string   `json:"arg_name,omitempty"`
+	Description     string   `json:"description,omitempty"`
+	MinArgs         int      `json:"min_args"`
+	MaxArgs         int      `json:"max_args"`
+	Required        bool     `json:"required"`
+	ValidValues     []string `json:"valid_values,omitempty"`
+	SuggestedValues []string `json:"suggested_values,omitempty"`
+	EnvVar          string   `json:"env_var,omitempty"`
+	Default         string   `json:"default,omitempty"`
+}
+
+// Schema - Returns a serializable description of the current command and all its child commands.
+// Options and commands are sorted by name and the help command is not included.
+// Options are only listed in the command that defines them, child commands inherit them.
+//
+// For example, to diff the CLI surface between releases:
+//
+//	b, err := json.MarshalIndent(opt.Schema(), "", "  ")
+func (gopt *GetOpt) Schema() *Schema {
+	return schema(gopt.programTree)
+}
+
+func schema(n *programTree) *Schema {
+	s := &Schema{
+		Name:        n.Name,
+		Description: n.Description,
+	}
+	for _, arg := range n.SynopsisArgs {
+		if arg.Arg == "" {
+			continue
+		}
+		s.Arguments = append(s.Arguments, SchemaArg{Name: arg.Arg, Description: arg.Description})
+	}
+	s.Args = nodeArgsSpec(n)
+	options := nodeOptions(n)
+	option.Sort(options)
+	for _, opt := range options {
+		// Options copied from the parent depend on the order of definition, list them only once.
+		if n.Parent != nil && n.Parent.ChildOptions[opt.Name] == opt {
+			continue
+		}
+		var aliases []string
+		if len(opt.Aliases) > 1 {
+			aliases = append(aliases, opt.Aliases[1:]...)
+		}
+		s.Options = append(s.Options, SchemaOption{
+			Name:            opt.Name,
+			Aliases:         aliases,
+			Type:            opt.OptType.String(),
+			ArgName:         opt.HelpArgName,
+			Description:     opt.Description,
+			MinArgs:         opt.MinArgs,
+			MaxArgs:         opt.MaxArgs,
+			Required:        opt.IsRequired,
+			ValidValues:     opt.ValidValues,
+			SuggestedValues: opt.SuggestedValues,
+			EnvVar:          opt.EnvVar,
+			Default:         opt.DefaultStr,
+		})
+	}
+	for _, command := range nodeCommands(n) {
+		s.Commands = append(s.Commands, schema(command))
+	}
+	return s
+}
+
+// SchemaChange - Breaking change between two schemas.
+type SchemaChange struct {
+	Command string `json:"command"`          // Command path, for example: "mytool deploy"
+	Option  string `json:"option,omitempty"` // Option name, empty for command changes
+	Message string `json:"message"`
+}
+
+func (c SchemaChange) String() string {
+	if c.Option == "" {
+		return fmt.Sprintf("%s: %s", c.Command, c.Message)
+	}
+	return fmt.Sprintf("%s --%s: %s", c.Command, c.Option, c.Message)
+}
+
+// CompareSchemas - Returns the breaking changes from the `oldSchema` to the `newSchema`.
+//
+// Reported changes are: removed commands, narrowed number of positional
+// arguments, removed options, removed aliases, aliases moved to a different
+// option, changed option types, new required options, options that became
+// required and narrowed valid values.
+//
+// Options are matched by any of their aliases so renaming an option while
+// keeping the old name as an alias is not a breaking change.
+func CompareSchemas(oldSchema, newSchema *Schema) []SchemaChange {
+	return compareSchemas(oldSchema.Name, oldSchema, newSchema)
+}
+
+func compareSchemas(path string, oldSchema, newSchema *Schema) []SchemaChange {
+	changes := []SchemaChange{}
+	add := func(option, format string, a ...interface{}) {
+		changes = append(changes, SchemaChange{Command: path, Option: option, Message: fmt.Sprintf(format, a...)})
+	}
+
+	// A nil spec accepts any number of arguments.
+	oldArgs, newArgs := ArgsSpec{Min: 0, Max: -1}, ArgsSpec{Min: 0, Max: -1}
+	if oldSchema.Args != nil {
+		oldArgs = *oldSchema.Args
+	}
+	if newSchema.Args != nil {
+		newArgs = *newSchema.Args
+	}
+	if newArgs.Min > oldArgs.Min {
+		add("", "minimum positional arguments raised from %d to %d", oldArgs.Min, newArgs.Min)
+	}
+	if newArgs.Max >= 0 {
+		if oldArgs.Max < 0 {
+			add("", "positional arguments limited to a maximum of %d", newArgs.Max)
+		} else if newArgs.Max < oldArgs.Max {
+			add("", "maximum positional arguments lowered from %d to %d", oldArgs.Max, newArgs.Max)
+		}
+	}
+
+	newOptions := map[string]*SchemaOption{}
+	for i, opt := range newSchema.Options {
+		newOptions[opt.Name] = &newSchema.Options[i]
+		for _, alias := range opt.Aliases {
+			newOptions[alias] = &newSchema.Options[i]
+		}
+	}
+	oldOptions := map[string]*SchemaOption{}
+	for i, opt := range oldSchema.Options {
+		oldOptions[opt.Name] = &oldSchema.Options[i]
+		for _, alias := range opt.Aliases {
+			oldOptions[alias] = &oldSchema.Options[i]
+		}
+	}
+
+	for _, o := range oldSchema.Options {
+		n, ok := newOptions[o.Name]
+		if !ok {
+			for _, alias := range o.Aliases {
+				if n, ok = newOptions[alias]; ok {
+					break
+				}
+			}
+		}
+		if !ok {
+			add(o.Name, "option removed")
+			continue
+		}
+		for _, alias := range append([]string{o.Name}, o.Aliases...) {
+			m, ok := newOptions[alias]
+			if !ok {
+				add(o.Name, "alias '%s' removed", alias)
+			} else if m != n {
+				add(o.Name, "alias '%s' moved to option '%s'", alias, m.Name)
+			}
+		}
+		if o.Type != n.Type {
+			add(o.Name, "type changed from '%s' to '%s'", o.Type, n.Type)
+		}
+		if !o.Required && n.Required {
+			add(o.Name, "option is now required")
+		}
+		if len(n.ValidValues) > 0 {
+			if len(o.ValidValues) == 0 {
+				add(o.Name, "valid values restricted to %q", n.ValidValues)
+			} else {
+				valid := map[string]bool{}
+				for _, v := range n.ValidValues {
+					valid[v] = true
+				}
+				for _, v := range o.ValidValues {
+					if !valid[v] {
+						add(o.Name, "valid value '%s' removed", v)
+					}
+				}
+			}
+		}
+	}
+	for _, n := range newSchema.Options {
+		if !n.Required {
+			continue
+		}
+		known := false
+		for _, alias := range append([]string{n.Name}, n.Aliases...) {
+			if _, ok := oldOptions[alias]; ok {
+				known = true
+				break
+			}
+		}
+		if !known {
+			add(n.Name, "new required option")
+		}
+	}
+
+	newCommands := map[string]*Schema{}
+	for _, command := range newSchema.Commands {
+		newCommands[command.Name] = command
+	}
+	for _, command := range oldSchema.Commands {
+		n, ok := newCommands[command.Name]
+		if !ok {
+			add("", "command '%s' removed", command.Name)
+			continue
+		}
+		changes = append(changes, compareSchemas(path+" "+command.Name, command, n)...)
+	}
+	return changes
+}
@@ -23,7 +23,25 @@ var ErrorMissingRequiredOption = "Missing required parameter '%s'"
 
 var ErrorMissingRequiredArgument = "ERROR: Missing required argument"
 
-var ErrorMissingRequiredNamedArgument = "ERROR: Missing %s"
+// ErrorMissingRequiredNamedArgument holds the text for a missing named positional argument.
+// It has a string placeholder '%s' for the name of the argument.
+var ErrorMissingRequiredNamedArgument = "Missing %s"
+
+// ErrorNotEnoughArguments holds the text for commands that received less positional arguments than required.
+// It has two int placeholders ('%d') for the minimum number of arguments and the number of arguments received.
+var ErrorNotEnoughArguments = "Not enough arguments, expected at least %d, got %d"
+
+// ErrorTooManyArguments holds the text for commands that received more positional arguments than accepted.
+// It has two int placeholders ('%d') for the maximum number of arguments and the number of arguments received.
+var ErrorTooManyArguments = "Too many arguments, expected at most %d, got %d"
+
+// ErrorExactArguments holds the text for commands that received a different number of positional arguments than the exact number accepted.
+// It has two int placeholders ('%d') for the number of arguments accepted and the number of arguments received.
+var ErrorExactArguments = "Wrong number of arguments, expected exactly %d, got %d"
+
+// ErrorInvalidValue holds the text for arguments that are not one of the option's valid values.
+// It has a string placeholder '%s' for the name of the option and a []string list of valid values.
+var ErrorInvalidValue = "wrong value for option '%s', valid values are %q"
 
 // ErrorArgumentIsNotKeyValue holds the text for Map type options where the argument is not of key=value type.
 // It has a string placeholder '%s' for the name of the option missing the argument.
@@ -40,12 +58,57 @@ var ErrorConvertToInt = "Argument error for option '%s': Can't convert string to
 
 var ErrorConvertArgumentToInt = "Argument error: Can't convert string to int: '%s'"
 
+// ErrorConvertNamedArgumentToInt holds the text for Int Conversion errors of named positional arguments.
+// It has two string placeholders ('%s'). The first one for the name of the argument and the second one for the argument that could not be converted.
+var ErrorConvertNamedArgumentToInt = "Argument error for '%s': Can't convert string to int: '%s'"
+
+// ErrorConvertToBool holds the text for Bool Coversion argument error.
+// It has two string placeholders ('%s'). The first one for the name of the option with the wrong argument and the second one for the argument that could not be converted.
+var ErrorConvertToBool = "Argument error for option '%s': Can't convert string to bool: '%s'"
+
 // ErrorConvertToFloat64 holds the text for Float64 Coversion argument error.
 // It has two string placeholders ('%s'). The first one for the name of the option with the wrong argument and the second one for the argument that could not be converted.
 var ErrorConvertToFloat64 = "Argument error for option '%s': Can't convert string to float64: '%s'"
 
 var ErrorConvertArgumentToFloat64 = "Argument error: Can't convert string to float64: '%s'"
 
+// ErrorConvertNamedArgumentToFloat64 holds the text for Float64 Conversion errors of named positional arguments.
+// It has two string placeholders ('%s'). The first one for the name of the argument and the second one for the argument that could not be converted.
+var ErrorConvertNamedArgumentToFloat64 = "Argument error for '%s': Can't convert string to float64: '%s'"
+
+// ErrorConvertToDuration holds the text for Duration Coversion argument error.
+// It has two string placeholders ('%s'). The first one for the name of the option with the wrong argument and the second one for the argument that could not be converted.
+var ErrorConvertToDuration = "Argument error for option '%s': Can't convert string to duration: '%s'"
+
+// ErrorConvertToValue holds the text for user defined Value argument errors.
+// It has two string placeholders ('%s'). The first one for the name of the option with the wrong argument and the second one for the argument that could not be set.
+// The third placeholder ('%s') is the error returned by the Value's Set method.
+var ErrorConvertToValue = "Argument error for option '%s': Can't set value '%s': %s"
+
+// ErrorEnvVar holds the text for errors reading the value of an environment variable.
+// It has two placeholders: '%s' for the name of the environment variable and '%s' for the underlying error.
+var ErrorEnvVar = "Environment variable '%s': %s"
+
+// ErrorEnvVarMultipleLines holds the text for slice and map environment variable values with more than one line.
+// It has a string placeholder '%s' for the separator.
+var ErrorEnvVarMultipleLines = "Values must be on a single line separated by '%s'"
+
+// ErrorUnknownShell holds the text for the completion command when the given shell is not supported.
+// It has a string placeholder '%s' for the given shell and a []string list of supported shells.
+var ErrorUnknownShell = "Unknown shell '%s', supported shells are %q"
+
+// ErrorConfigFile holds the text for errors reading or decoding a configuration file.
+// It has two placeholders: '%s' for the path of the file and '%s' for the underlying error.
+var ErrorConfigFile = "Error in config file '%s': %s"
+
+// ErrorConfigUnknownKey holds the text for configuration file keys that don't match an option or command.
+// It has two string placeholders ('%s'). The first one for the key and the second one for the path of the file.
+var ErrorConfigUnknownKey = "Unknown option '%s' in config file '%s'"
+
+// ErrorUnknownDocFormat holds the text for reference documentation formats that are not supported.
+// It has a '%d' placeholder for the given format.
+var ErrorUnknownDocFormat = "Unknown doc format: %d"
+
 // WarningOnUnknown holds the text for the unknown option message.
 // It has a string placeholder '%s' for the name of the option missing the argument.
 // This one includes the WARNING prefix because it is printed directly to the Writer output.
@@ -53,6 +116,18 @@ var WarningOnUnknown = "WARNING: Unknown option '%s'"
 
 var MessageOnUnknown = "Unknown option '%s'"
 
+// ErrorUnknownCommand holds the text for a mistyped command name.
+// It has a string placeholder '%s' for the argument passed.
+var ErrorUnknownCommand = "Unknown command '%s'"
+
+// ErrorMutuallyExclusive holds the text for mutually exclusive options called together.
+// It has a string placeholder '%s' for the quoted list of the options used.
+var ErrorMutuallyExclusive = "Options %s are mutually exclusive"
+
+// MessageDidYouMean holds the text appended to unknown option and command messages.
+// It has a string placeholder '%s' for the quoted list of suggestions.
+var MessageDidYouMean = ", did you mean %s?"
+
 // MessageOnInterrupt holds the text for the message to be printed when an interrupt is received.
 var MessageOnInterrupt = "Interrupt signal received"
 
@@ -73,3 +148,27 @@ var HelpArgumentsHeader = "ARGUMENTS"
 
 // HelpOptionsHeader holds the header text for the option list
 var HelpOptionsHeader = "OPTIONS"
+
+// HelpEnvironmentHeader holds the header text for the environment variable list
+var HelpEnvironmentHeader = "ENVIRONMENT"
+
+// DocCommandColumn holds the reference documentation column header for the command names
+var DocCommandColumn = "Command"
+
+// DocArgumentColumn holds the reference documentation column header for the argument names
+var DocArgumentColumn = "Argument"
+
+// DocOptionColumn holds the reference documentation column header for the option synopsis
+var DocOptionColumn = "Option"
+
+// DocDescriptionColumn holds the reference documentation column header for the descriptions
+var DocDescriptionColumn = "Description"
+
+// DocDefaultColumn holds the reference documentation column header for the option defaults
+var DocDefaultColumn = "Default"
+
+// DocValidValuesColumn holds the reference documentation column header for the option valid values
+var DocValidValuesColumn = "Valid values"
+
+// DocEnvironmentColumn holds the reference documentation column header for the option environment variables
+var DocEnvironmentColumn = "Environment"
@@ -18,8 +18,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/DavidGamba/go-getoptions/internal/completion"
 	"github.com/DavidGamba/go-getoptions/internal/help"
 	"github.com/DavidGamba/go-getoptions/internal/option"
 	"github.com/DavidGamba/go-getoptions/text"
@@ -188,7 +190,7 @@ func (gopt *GetOpt) ArgCompletions(list ...string) *GetOpt {
 }
 
 // ArgCompletionsFn - Function receiver for custom completions.
-// The `target` argument indicates "bash" or "zsh" for the completion targets.
+// The `target` argument indicates "bash", "zsh", "fish" or "powershell" for the completion targets.
 //
 // NOTE: Bash completions have = as a special char and results should be trimmed from the = on.
 // This should not be done for Zsh.
@@ -206,6 +208,64 @@ func (gopt *GetOpt) ArgCompletionsFns(fn ...ArgCompletionsFn) *GetOpt {
 	return gopt
 }
 
+// ArgCompletionsContextFn - Function receiver for custom completions that
+// depend on the options parsed so far.
+// The `target` argument indicates "bash", "zsh", "fish" or "powershell" for the completion targets.
+//
+// NOTE: Bash completions have = as a special char and results should be trimmed from the = on.
+// This should not be done for Zsh.
+type ArgCompletionsContextFn func(target string, parsed ParsedOptions, previousArgs []string, partialCompletion string) []string
+
+// ArgCompletionsContextFns - Allows to define custom argument completion
+// functions that get lazily called with a read-only view of the options
+// parsed before the argument being completed.
+//
+// For example, to list the instances of the region given with `--region`:
+//
+//	opt.ArgCompletionsContextFns(func(target string, parsed getoptions.ParsedOptions, prev []string, partial string) []string {
+//		return listInstances(parsed.Value("region").(string))
+//	})
+func (gopt *GetOpt) ArgCompletionsContextFns(fn ...ArgCompletionsContextFn) *GetOpt {
+	gopt.programTree.SuggestionContextFns = append(gopt.programTree.SuggestionContextFns, fn...)
+	return gopt
+}
+
+// Completion - Completion candidate with a description.
+type Completion struct {
+	Value       string
+	Description string
+}
+
+// ArgCompletionsDescribedFn - Function receiver for custom completions with descriptions.
+// The `target` argument indicates "bash", "zsh", "fish" or "powershell" for the completion targets.
+//
+// Descriptions are shown by zsh (see `opt.ZshCompletionScript`), fish and PowerShell.
+type ArgCompletionsDescribedFn func(target string, previousArgs []string, partialCompletion string) []Completion
+
+// ArgCompletionsDescribedFns - Allows to define custom argument completion
+// functions that return candidates with descriptions.
+// They get lazily called like the functions defined with `opt.ArgCompletionsFns`.
+func (gopt *GetOpt) ArgCompletionsDescribedFns(fn ...ArgCompletionsDescribedFn) *GetOpt {
+	gopt.programTree.SuggestionDescribedFns = append(gopt.programTree.SuggestionDescribedFns, fn...)
+	return gopt
+}
+
+// ArgCompleteFiles - Add the files relative to the current dir to the completion list for any non-option argument.
+// When globs are given, for example `*.json`, only matching files are suggested.
+// Dirs are always suggested so they can be navigated.
+func (gopt *GetOpt) ArgCompleteFiles(globs ...string) *GetOpt {
+	return gopt.ArgCompletionsFns(func(target string, previousArgs []string, partial string) []string {
+		return completion.Files(partial, globs, false)
+	})
+}
+
+// ArgCompleteDirs - Add the dirs relative to the current dir to the completion list for any non-option argument.
+func (gopt *GetOpt) ArgCompleteDirs() *GetOpt {
+	return gopt.ArgCompletionsFns(func(target string, previousArgs []string, partial string) []string {
+		return completion.Files(partial, nil, true)
+	})
+}
+
 // UnsetOptions - Unsets inherited options from parent program and parent commands.
 // This is useful when writing wrappers around other commands.
 //
@@ -237,8 +297,11 @@ func (gopt *GetOpt) NewCommand(name string, description string) *GetOpt {
 		Parent:          gopt.programTree,
 		Level:           gopt.programTree.Level + 1,
 		mapKeysToLower:  gopt.programTree.mapKeysToLower,
+		envSeparator:    gopt.programTree.envSeparator,
+		envPrefix:       gopt.programTree.envPrefix,
 		unknownMode:     gopt.programTree.unknownMode,
 		requireOrder:    gopt.programTree.requireOrder,
+		didYouMean:      gopt.programTree.didYouMean,
 	}
 
 	// TODO: Copying options from parent to child can't be done on declaration
@@ -310,6 +373,16 @@ func (gopt *GetOpt) Parse(args []string) ([]string, error) {
 		if zsh != "" {
 			completionTarget = "zsh"
 		}
+		// Set by the native zsh completion script that can display descriptions
+		zshDescribe := os.Getenv("ZSH_DESCRIBE") != ""
+		fish := os.Getenv("FISHSHELL")
+		if fish != "" {
+			completionTarget = "fish"
+		}
+		powershell := os.Getenv("POWERSHELL")
+		if powershell != "" {
+			completionTarget = "powershell"
+		}
 		// COMP_LINE has a single trailing space when the completion isn't complete and 2 when it is
 		re := regexp.MustCompile(`\s+`)
 		compLineParts := re.Split(compLine, -1)
@@ -334,7 +407,7 @@ func (gopt *GetOpt) Parse(args []string) ([]string, error) {
 		// NOTE: Bash completions have = as a special char and results should be trimmed form the = on.
 		Logger.SetPrefix("\n")
 		Logger.Printf("mode: %s, COMP_LINE: '%s', parts: %#v, args: %#v\n", completionTarget, compLine, compLineParts, args)
-		_, completions, err := parseCLIArgs(completionTarget, gopt.programTree, compLineParts, Normal)
+		node, completions, err := parseCLIArgs(completionTarget, gopt.programTree, compLineParts, Normal)
 		if err != nil {
 			fmt.Fprintf(Writer, "\nERROR: %s\n", err)
 			exitFn(124) // programmable completion restarts from the beginning, with an attempt to find a new compspec for that command.
@@ -342,6 +415,7 @@ func (gopt *GetOpt) Parse(args []string) ([]string, error) {
 			// Ignore errors in completion mode
 			return nil, nil
 		}
+		completions = formatCompletions(completionTarget, zshDescribe, node, completions)
 		Logger.Printf("completions: %#v\n", completions)
 		fmt.Fprintln(completionWriter, strings.Join(completions, "\n"))
 		exitFn(124) // programmable completion restarts from the beginning, with an attempt to find a new compspec for that command.
@@ -361,6 +435,35 @@ func (gopt *GetOpt) Parse(args []string) ([]string, error) {
 		return nil, err
 	}
 
+	// When the help is called the program won't run, so env vars, configuration
+	// files and arguments aren't validated, they shouldn't prevent the help from showing.
+	helpOpt, ok := node.ChildOptions[node.HelpCommandName]
+	helpCalled := node.HelpCommandName != "" && ok && helpOpt.Called
+
+	// Report env var errors for the options of the final node unless the CLI overrides them.
+	if !helpCalled {
+		envErrors := []error{}
+		for k, option := range node.ChildOptions {
+			if k == option.Name && option.EnvError != nil && !option.Called {
+				envErrors = append(envErrors, option.EnvError)
+			}
+		}
+		if len(envErrors) > 0 {
+			sort.Slice(envErrors, func(i, j int) bool { return envErrors[i].Error() < envErrors[j].Error() })
+			messages := []string{}
+			for _, e := range envErrors {
+				messages = append(messages, e.Error())
+			}
+			// The first error is the one matched by errors.As
+			return nil, &wrappedError{msg: strings.Join(messages, "\n"), err: envErrors[0]}
+		}
+
+		err = applyConfigFiles(gopt.programTree, node)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Only validate required options at the parse call when the final node is the parent
 	// This to enable handling the help option in a command
 	if gopt.finalNode.Parent == nil {
@@ -378,12 +481,49 @@ func (gopt *GetOpt) Parse(args []string) ([]string, error) {
 	}
 
 	for _, option := range node.UnknownOptions {
+		var suggestions []string
+		if node.didYouMean {
+			suggestions = closestNames(option.Name, nodeOptionNames(node))
+		}
 		// Check for unknown mode at the node that we want to validate
 		switch gopt.finalNode.unknownMode {
 		case Fail:
-			return nil, fmt.Errorf(text.MessageOnUnknown, option.Name)
+			return nil, &UnknownOptionError{Option: option.Name, UsedAlias: option.Name, Value: option.Verbatim, Suggestions: suggestions}
 		case Warn:
-			fmt.Fprintf(Writer, text.WarningOnUnknown+"\n", option.Name)
+			fmt.Fprintf(Writer, text.WarningOnUnknown+"%s\n", option.Name, didYouMean(suggestions, optionDashes))
+		}
+	}
+
+	// A mistyped command name would otherwise be passed as an argument.
+	// The help command is added to every node so it is not a candidate, leaf commands take any argument.
+	if node.didYouMean && len(node.ChildText) > 0 && !strings.HasPrefix(node.ChildText[0], "-") {
+		commands := []string{}
+		for name := range node.ChildCommands {
+			if name == node.HelpCommandName {
+				continue
+			}
+			commands = append(commands, name)
+		}
+		if len(commands) > 0 {
+			suggestions := closestNames(node.ChildText[0], commands)
+			if len(suggestions) > 0 {
+				return nil, &UnknownCommandError{Command: node.ChildText[0], Suggestions: suggestions}
+			}
+		}
+	}
+
+	if !helpCalled {
+		err = validateExclusive(node)
+		if err != nil {
+			return nil, err
+		}
+		err = validateArgs(node, node.ChildText)
+		if err != nil {
+			return nil, err
+		}
+		err = bindArgs(node, node.ChildText)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -0,0 +1,206 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2025  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/DavidGamba/go-getoptions/internal/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// ParsedOptions - Read-only view of the options parsed before the element being completed.
+// The methods behave like their `GetOpt` counterparts.
+type ParsedOptions interface {
+	Called(name string) bool
+	CalledAs(name string) string
+	Value(name string) interface{}
+	Source(name string) ValueSource
+}
+
+// parsedOptions - ParsedOptions implementation over the options of the command being completed.
+type parsedOptions map[string]*option.Option
+
+func (p parsedOptions) gopt() *GetOpt {
+	return &GetOpt{programTree: &programTree{ChildOptions: p}}
+}
+
+func (p parsedOptions) Called(name string) bool        { return p.gopt().Called(name) }
+func (p parsedOptions) CalledAs(name string) string    { return p.gopt().CalledAs(name) }
+func (p parsedOptions) Value(name string) interface{}  { return p.gopt().Value(name) }
+func (p parsedOptions) Source(name string) ValueSource { return p.gopt().Source(name) }
+
+// CompletionCommand - Declares a command that prints the completion script for the given shell.
+// Supported shells are bash, zsh, fish and powershell.
+//
+// For example, with `opt.CompletionCommand("completion")` users can enable completions with:
+//
+//	eval "$(my-go-program completion bash)"
+//
+// NOTE: Define after all options have been defined.
+func (gopt *GetOpt) CompletionCommand(name string) *GetOpt {
+	cmd := gopt.NewCommand(name, "Print the shell completion script")
+	cmd.HelpSynopsisArg("<shell>", strings.Join(completionShells, ", "))
+	cmd.ArgCompletions(completionShells...)
+	cmd.SetCommandFn(runCompletion)
+	return cmd
+}
+
+func runCompletion(ctx context.Context, opt *GetOpt, args []string) error {
+	shell, _, err := opt.GetRequiredArg(args)
+	if err != nil {
+		return err
+	}
+	root := opt.programTree
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	rootOpt := &GetOpt{programTree: root}
+	script := ""
+	switch shell {
+	case "bash":
+		script = rootOpt.BashCompletionScript()
+	case "zsh":
+		script = rootOpt.ZshCompletionScript()
+	case "fish":
+		script = rootOpt.FishCompletionScript()
+	case "powershell":
+		script = rootOpt.PowerShellCompletionScript()
+	default:
+		return fmt.Errorf("%w"+text.ErrorUnknownShell, ErrorParsing, shell, completionShells)
+	}
+	fmt.Fprint(completionWriter, script)
+	return nil
+}
+
+// BashCompletionScript - Returns the bash line that registers the program completions.
+//
+//	complete -o default -C my-go-program my-go-program
+//
+// NOTE: Call on the top level GetOpt object.
+func (gopt *GetOpt) BashCompletionScript() string {
+	name := gopt.programTree.Name
+	return fmt.Sprintf("complete -o default -C %s %s\n", name, name)
+}
+
+// FishCompletionScript - Returns the fish snippet that registers the program completions.
+//
+// Add it to `~/.config/fish/completions/<program>.fish`:
+//
+//	complete -c my-go-program -f -a '(env COMP_LINE=(commandline -cp) FISHSHELL=true my-go-program)'
+//
+// NOTE: Call on the top level GetOpt object.
+func (gopt *GetOpt) FishCompletionScript() string {
+	name := gopt.programTree.Name
+	return fmt.Sprintf("complete -c %s -f -a '(env COMP_LINE=(commandline -cp) FISHSHELL=true %s)'\n", name, name)
+}
+
+// PowerShellCompletionScript - Returns the PowerShell script that registers the program completions.
+//
+// Add it to your PowerShell profile (`$PROFILE`).
+//
+// NOTE: Call on the top level GetOpt object.
+func (gopt *GetOpt) PowerShellCompletionScript() string {
+	name := gopt.programTree.Name
+	return fmt.Sprintf(`Register-ArgumentCompleter -Native -CommandName '%s' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $line = $commandAst.Extent.Text
+    $line = $line.Substring(0, [Math]::Min($line.Length, $cursorPosition - $commandAst.Extent.StartOffset))
+    if ($wordToComplete -eq '') { $line += ' ' }
+    $env:COMP_LINE = $line
+    $env:POWERSHELL = 'true'
+    & '%s' | ForEach-Object {
+        $text, $type, $toolTip = $_ -split "`+"`t"+`", 3
+        [System.Management.Automation.CompletionResult]::new($text, $text, $type, $toolTip)
+    }
+    Remove-Item Env:COMP_LINE, Env:POWERSHELL
+}
+`, name, name)
+}
+
+// ZshCompletionScript - Returns the native zsh completion script that displays the option, command and argument descriptions.
+//
+// Add it to your zsh profile after `compinit`.
+// It replaces the `bashcompinit` based setup.
+//
+// NOTE: Call on the top level GetOpt object.
+func (gopt *GetOpt) ZshCompletionScript() string {
+	name := gopt.programTree.Name
+	fn := "_" + strings.NewReplacer("-", "_", ".", "_").Replace(name)
+	return fmt.Sprintf(`%s() {
+    local -a completions
+    local line="${(j: :)words[1,CURRENT]}"
+    completions=("${(@f)$(COMP_LINE="$line" ZSHELL=true ZSH_DESCRIBE=true %s 2>/dev/null)}")
+    _describe 'values' completions
+}
+compdef %s %s
+`, fn, name, fn, name)
+}
+
+// completionDescription - Returns the description of the option or command matching the completion.
+func completionDescription(node *programTree, c string) string {
+	description := ""
+	if strings.HasPrefix(c, "-") {
+		name := strings.TrimSuffix(strings.TrimLeft(c, "-"), "=")
+		if opt, ok := node.ChildOptions[name]; ok {
+			description = opt.Description
+		}
+	} else if command, ok := node.ChildCommands[c]; ok {
+		description = command.Description
+	}
+	return strings.Join(strings.Fields(description), " ")
+}
+
+// formatCompletions - Formats the completions for the given target.
+// Completions can carry a description after a tab, otherwise the option or command description is used.
+func formatCompletions(target string, zshDescribe bool, node *programTree, completions []string) []string {
+	out := []string{}
+	for _, c := range completions {
+		// The extra single dir entry that prevents bash from adding a trailing space isn't needed by shells that display candidates.
+		if strings.HasSuffix(c, "/ ") && (target == "fish" || target == "powershell" || zshDescribe) {
+			continue
+		}
+		value, description, ok := strings.Cut(c, "\t")
+		if !ok {
+			description = completionDescription(node, c)
+		}
+		switch {
+		case target == "zsh" && zshDescribe:
+			// _describe uses ':' as the separator
+			c = strings.ReplaceAll(value, ":", `\:`)
+			if description != "" {
+				c += ":" + description
+			}
+		case target == "fish":
+			c = value
+			if description != "" {
+				c += "\t" + description
+			}
+		case target == "powershell":
+			// The type is a CompletionResultType: ParameterName for options and ParameterValue for everything else.
+			resultType := "ParameterValue"
+			if strings.HasPrefix(value, "-") {
+				resultType = "ParameterName"
+			}
+			if description == "" {
+				// CompletionResult requires a non empty tool tip
+				description = value
+			}
+			c = value + "\t" + resultType + "\t" + description
+		default:
+			c = value
+		}
+		out = append(out, c)
+	}
+	return out
+}
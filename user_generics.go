@@ -0,0 +1,119 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2025  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+
+	"github.com/DavidGamba/go-getoptions/internal/option"
+)
+
+// ParseFn - Function signature used by the generic option constructors to
+// convert a CLI argument into a value of type T.
+type ParseFn[T any] func(s string) (T, error)
+
+// genericValue - Value implementation backed by a pointer and a parse function.
+type genericValue[T any] struct {
+	p     *T
+	parse ParseFn[T]
+}
+
+func (v *genericValue[T]) String() string { return fmt.Sprintf("%v", *v.p) }
+
+func (v *genericValue[T]) Get() interface{} { return *v.p }
+
+func (v *genericValue[T]) Set(s string) error {
+	t, err := v.parse(s)
+	if err != nil {
+		return err
+	}
+	*v.p = t
+	return nil
+}
+
+// Snapshot - Returns a function that restores the current value, used to replace env var values with CLI values.
+func (v *genericValue[T]) Snapshot() func() {
+	t := *v.p
+	return func() { *v.p = t }
+}
+
+// genericSliceValue - Value implementation that appends to a slice pointer.
+type genericSliceValue[T any] struct {
+	p     *[]T
+	parse ParseFn[T]
+}
+
+func (v *genericSliceValue[T]) String() string { return fmt.Sprintf("%v", *v.p) }
+
+func (v *genericSliceValue[T]) Get() interface{} { return *v.p }
+
+func (v *genericSliceValue[T]) Set(s string) error {
+	t, err := v.parse(s)
+	if err != nil {
+		return err
+	}
+	*v.p = append(*v.p, t)
+	return nil
+}
+
+// Snapshot - Returns a function that restores the current slice, used to replace env var values with CLI values.
+func (v *genericSliceValue[T]) Snapshot() func() {
+	s := append([]T{}, *v.p...)
+	return func() { *v.p = s }
+}
+
+// Option - define an option of any type T and its aliases.
+// The given `parse` function converts the CLI argument into a value of type T.
+// If not called, the return value will be that of the given default `def`.
+//
+// For example:
+//
+//	u := getoptions.Option(opt, "url", &url.URL{}, url.Parse, opt.Required())
+func Option[T any](gopt *GetOpt, name string, def T, parse ParseFn[T], fns ...ModifyFn) *T {
+	OptionVar(gopt, &def, name, def, parse, fns...)
+	return &def
+}
+
+// OptionVar - define an option of any type T and its aliases.
+// The result will be available through the variable marked by the given pointer.
+// If not called, the return value will be that of the given default `def`.
+func OptionVar[T any](gopt *GetOpt, p *T, name string, def T, parse ParseFn[T], fns ...ModifyFn) {
+	*p = def
+	gopt.Var(&genericValue[T]{p: p, parse: parse}, name, fns...)
+}
+
+// SliceOption - define a `[]T` option of any type T and its aliases.
+// The given `parse` function converts each CLI argument into a value of type T.
+//
+// SliceOption will accept multiple calls to the same option and append them
+// to the `[]T`.
+// The min and max behave the same as in `opt.StringSlice`.
+func SliceOption[T any](gopt *GetOpt, name string, min, max int, parse ParseFn[T], fns ...ModifyFn) *[]T {
+	s := []T{}
+	SliceOptionVar(gopt, &s, name, min, max, parse, fns...)
+	return &s
+}
+
+// SliceOptionVar - define a `[]T` option of any type T and its aliases.
+// The result will be available through the variable marked by the given pointer.
+//
+// SliceOptionVar will accept multiple calls to the same option and append them
+// to the `[]T`.
+// The min and max behave the same as in `opt.StringSliceVar`.
+func SliceOptionVar[T any](gopt *GetOpt, p *[]T, name string, min, max int, parse ParseFn[T], fns ...ModifyFn) {
+	n := option.New(name, option.ValueRepeatType, &genericSliceValue[T]{p: p, parse: parse})
+	n.MinArgs = min
+	n.MaxArgs = max
+	gopt.programTree.AddChildOption(name, n)
+	for _, fn := range fns {
+		fn(gopt, n)
+	}
+	gopt.bindEnvPrefix(n)
+	n.Synopsis()
+}
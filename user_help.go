@@ -11,6 +11,7 @@ package getoptions
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/DavidGamba/go-getoptions/internal/help"
 	"github.com/DavidGamba/go-getoptions/internal/option"
@@ -28,6 +29,7 @@ const (
 	HelpCommandList
 	HelpOptionList
 	HelpCommandInfo
+	HelpEnvironment
 )
 
 func getCurrentNodeName(n *programTree) string {
@@ -38,6 +40,36 @@ func getCurrentNodeName(n *programTree) string {
 	return n.Name
 }
 
+// nodeOptions - Returns the options of the node without aliases.
+func nodeOptions(n *programTree) []*option.Option {
+	options := []*option.Option{}
+	for k, option := range n.ChildOptions {
+		// filter out aliases
+		if k != option.Name {
+			continue
+		}
+		options = append(options, option)
+	}
+	return options
+}
+
+// nodeCommands - Returns the sorted child commands of the node without the help command.
+func nodeCommands(n *programTree) []*programTree {
+	names := []string{}
+	for _, command := range n.ChildCommands {
+		if command.Name == n.HelpCommandName {
+			continue
+		}
+		names = append(names, command.Name)
+	}
+	sort.Strings(names)
+	commands := []*programTree{}
+	for _, name := range names {
+		commands = append(commands, n.ChildCommands[name])
+	}
+	return commands
+}
+
 // Help - Default help string that is composed of all available sections.
 func (gopt *GetOpt) Help(sections ...HelpSection) string {
 	if gopt.finalNode != nil {
@@ -101,6 +133,12 @@ func helpOutput(node *programTree, sections ...HelpSection) string {
 			}
 		case HelpOptionList:
 			helpTxt += help.OptionList(node.SynopsisArgs, options)
+		case HelpEnvironment:
+			environment := help.EnvironmentList(options)
+			if environment != "" {
+				helpTxt += environment
+				helpTxt += "\n"
+			}
 		case HelpCommandInfo:
 			// Index of 1 because when there is a child command, help is always injected
 			if node.HelpCommandName != "" && len(node.ChildCommands) > 1 {
@@ -123,8 +161,8 @@ func helpOutput(node *programTree, sections ...HelpSection) string {
 func (gopt *GetOpt) HelpCommand(name string, fns ...ModifyFn) {
 	// TODO: Think about panicking on double call to this method
 
-	// Define help option
-	gopt.Bool(name, false, fns...)
+	// Define help option, the help is never read from the environment
+	gopt.Bool(name, false, append(fns, gopt.NoEnv())...)
 
 	cmdFn := func(parent *programTree) {
 		suggestions := []string{}
@@ -9,10 +9,16 @@
 package getoptions
 
 import (
+	"encoding/csv"
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/DavidGamba/go-getoptions/internal/completion"
 	"github.com/DavidGamba/go-getoptions/internal/option"
+	"github.com/DavidGamba/go-getoptions/text"
 )
 
 // ModifyFn - Function signature for functions that modify an option.
@@ -73,8 +79,6 @@ func (gopt *GetOpt) Required(msg ...string) ModifyFn {
 // GetEnv - Will read an environment variable if set.
 // Precedence higher to lower: CLI option, environment variable, option default.
 //
-// Currently, only `opt.Bool`, `opt.BoolVar`, `opt.String`, and `opt.StringVar` are supported.
-//
 // When an environment variable that matches the variable from opt.GetEnv is
 // set, opt.GetEnv will set opt.Called(name) to true and will set
 // opt.CalledAs(name) to the name of the environment variable used.
@@ -85,31 +89,147 @@ func (gopt *GetOpt) Required(msg ...string) ModifyFn {
 // "true" or "false" are valid.  They can be provided in any casing, for
 // example: "true", "True" or "TRUE".
 //
-// NOTE: Non supported option types behave with a No-Op when `opt.GetEnv` is defined.
+// Slice options read a list of values separated by the env separator (',' by
+// default, see `opt.SetEnvSeparator`) using CSV quoting rules, for example:
+// `a,"b,c",d`.
+// Map options read a list of `key=value` entries with the same rules.
+// When a slice, map or increment option is passed in the command line, the
+// CLI values replace the env var values.
+// Increment options read an integer.
+//
+// Values that can't be converted to the option type, including bool values
+// other than "true" or "false", are reported as an error by `opt.Parse`
+// wrapped in `ErrorParsing`, for example:
+//
+//	Environment variable 'MYTOOL_PORT': Argument error for option 'port': Can't convert string to int: 'abc'
+//
+// The error is not reported when the option is passed in the command line.
 func (gopt *GetOpt) GetEnv(name string) ModifyFn {
 	return func(parent *GetOpt, opt *option.Option) {
 		opt.SetEnvVar(name)
 		value := os.Getenv(name)
-		if value != "" {
-			switch opt.OptType {
-			case option.BoolType:
-				v := strings.ToLower(value)
-				if v == "true" || v == "false" {
-					_ = opt.Save(v)
-					opt.SetCalled(name)
-				}
-			case option.StringType,
-				option.IntType,
-				option.Float64Type,
-				option.StringOptionalType,
-				option.IntOptionalType,
-				option.Float64OptionalType:
-
-				_ = opt.Save(value)
-				opt.SetCalled(name)
+		if value == "" {
+			return
+		}
+		// Conversion errors name the option, the wrapping error names the env var.
+		opt.UsedAlias = opt.Name
+		// CLI values replace the env var value instead of being added to it.
+		opt.EnvReset = opt.Snapshot()
+		var err error
+		switch opt.OptType {
+		case option.BoolType:
+			v := strings.ToLower(value)
+			if v != "true" && v != "false" {
+				err = &ConversionError{Option: opt.Name, UsedAlias: opt.Name, Value: value, Type: opt.OptType.String(), msg: fmt.Sprintf(text.ErrorConvertToBool, opt.Name, value)}
+				break
+			}
+			err = opt.Save(v)
+		case option.IncrementType:
+			var i int
+			i, err = strconv.Atoi(value)
+			if err != nil {
+				err = &ConversionError{Option: opt.Name, UsedAlias: opt.Name, Value: value, Type: opt.OptType.String(), Err: err, msg: fmt.Sprintf(text.ErrorConvertToInt, opt.Name, value)}
+				break
 			}
+			opt.SetInt(i)
+		case option.StringRepeatType,
+			option.IntRepeatType,
+			option.Float64RepeatType,
+			option.DurationRepeatType,
+			option.ValueRepeatType,
+			option.StringMapType:
+
+			var values []string
+			values, err = splitEnvValue(value, parent.programTree.envSeparator)
+			if err != nil {
+				break
+			}
+			opt.MapKeysToLower = parent.programTree.mapKeysToLower
+			err = opt.Save(values...)
+		default:
+			err = opt.Save(value)
+		}
+		opt.UsedAlias = ""
+		if err != nil {
+			opt.EnvError = &wrappedError{msg: fmt.Sprintf(text.ErrorEnvVar, name, err), err: optionError(err)}
+			return
 		}
+		opt.SetCalled(name)
+		opt.SetSource(option.SourceEnv, name)
+	}
+}
+
+// splitEnvValue - Splits an env var value using CSV quoting rules.
+func splitEnvValue(value string, separator rune) ([]string, error) {
+	if separator == 0 {
+		separator = ','
+	}
+	r := csv.NewReader(strings.NewReader(value))
+	r.Comma = separator
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	// Values after a newline would otherwise be dropped.
+	if len(records) > 1 {
+		return nil, fmt.Errorf(text.ErrorEnvVarMultipleLines, string(separator))
+	}
+	if len(records) == 0 {
+		return []string{}, nil
+	}
+	return records[0], nil
+}
+
+// SetEnvSeparator - Sets the separator used by `opt.GetEnv` to split the
+// environment variable value of slice and map options.
+// Defaults to ','.
+//
+// NOTE: Set before defining options and commands.
+func (gopt *GetOpt) SetEnvSeparator(separator rune) *GetOpt {
+	gopt.programTree.envSeparator = separator
+	return gopt
+}
+
+// SetEnvPrefix - Binds every option to an environment variable derived from
+// the given prefix, the command path and the option name:
+// `PREFIX_[COMMAND_]OPTION_NAME`.
+// Names are upper cased and dashes are replaced with underscores.
+//
+// For example, with `opt.SetEnvPrefix("MYTOOL")` the option `--dry-run` on the
+// command `deploy` reads `MYTOOL_DEPLOY_DRY_RUN`.
+//
+// The binding follows the same rules as `opt.GetEnv`.
+// Options with an explicit `opt.GetEnv` keep that env var and options with
+// `opt.NoEnv` are not bound.
+//
+// NOTE: Set before defining options and commands.
+func (gopt *GetOpt) SetEnvPrefix(prefix string) *GetOpt {
+	gopt.programTree.envPrefix = prefix
+	return gopt
+}
+
+// NoEnv - Skips the automatic env var binding set with `opt.SetEnvPrefix`.
+func (gopt *GetOpt) NoEnv() ModifyFn {
+	return func(parent *GetOpt, opt *option.Option) {
+		opt.EnvDisabled = true
+	}
+}
+
+// bindEnvPrefix - Binds the option to the env var derived from the prefix set with opt.SetEnvPrefix.
+func (gopt *GetOpt) bindEnvPrefix(opt *option.Option) {
+	if gopt.programTree.envPrefix == "" || opt.EnvVar != "" || opt.EnvDisabled {
+		return
 	}
+	parts := []string{opt.Name}
+	for n := gopt.programTree; n.Parent != nil; n = n.Parent {
+		parts = append([]string{n.Name}, parts...)
+	}
+	parts = append([]string{gopt.programTree.envPrefix}, parts...)
+	name := strings.ToUpper(strings.Join(parts, "_"))
+	name = strings.NewReplacer("-", "_", " ", "_", ".", "_").Replace(name)
+	gopt.GetEnv(name)(gopt, opt)
 }
 
 // ArgName - Add an argument name to an option for use in automated help.
@@ -143,9 +263,67 @@ func (gopt *GetOpt) SuggestedValues(values ...string) ModifyFn {
 }
 
 // SuggestedValuesFn - adds a dynamic list of suggestions to the autocompletion for the option.
+// It can be combined with `opt.CompleteFiles` and `opt.CompleteDirs`, the suggestions of all of them are used.
+//
+// NOTE: Calling it more than once for the same option adds the suggestions of every function.
+// Before v0.34.0 the last function replaced the previous ones.
 func (gopt *GetOpt) SuggestedValuesFn(fn option.ValueCompletionsFn) ModifyFn {
 	return func(parent *GetOpt, opt *option.Option) {
+		addSuggestedValuesFn(opt, fn)
+	}
+}
+
+// ValueCompletionsContextFn - Function receiver for custom option value
+// completions that depend on the options parsed so far.
+// The `target` argument indicates "bash", "zsh", "fish" or "powershell" for the completion targets.
+type ValueCompletionsContextFn func(target string, parsed ParsedOptions, partialCompletion string) []string
+
+// SuggestedValuesContextFn - adds a dynamic list of suggestions to the
+// autocompletion for the option.
+// The function receives a read-only view of the options parsed before the option being completed.
+//
+// For example, `--region us-east-1 --instance=<TAB>` can list the instances of the given region:
+//
+//	opt.String("instance", "", opt.SuggestedValuesContextFn(func(target string, parsed getoptions.ParsedOptions, partial string) []string {
+//		return listInstances(parsed.Value("region").(string))
+//	}))
+func (gopt *GetOpt) SuggestedValuesContextFn(fn ValueCompletionsContextFn) ModifyFn {
+	return func(parent *GetOpt, opt *option.Option) {
+		opt.SuggestedValuesContextFn = func(target string, options map[string]*option.Option, partial string) []string {
+			return fn(target, parsedOptions(options), partial)
+		}
+	}
+}
+
+// CompleteFiles - adds the files relative to the current dir to the autocompletion for the option.
+// When globs are given, for example `*.json`, only matching files are suggested.
+// Dirs are always suggested so they can be navigated.
+func (gopt *GetOpt) CompleteFiles(globs ...string) ModifyFn {
+	return func(parent *GetOpt, opt *option.Option) {
+		addSuggestedValuesFn(opt, func(target string, partial string) []string {
+			return completion.Files(partial, globs, false)
+		})
+	}
+}
+
+// CompleteDirs - adds the dirs relative to the current dir to the autocompletion for the option.
+func (gopt *GetOpt) CompleteDirs() ModifyFn {
+	return func(parent *GetOpt, opt *option.Option) {
+		addSuggestedValuesFn(opt, func(target string, partial string) []string {
+			return completion.Files(partial, nil, true)
+		})
+	}
+}
+
+// addSuggestedValuesFn - Adds the suggestions of fn to the ones of the option's current SuggestedValuesFn.
+func addSuggestedValuesFn(opt *option.Option, fn option.ValueCompletionsFn) {
+	previous := opt.SuggestedValuesFn
+	if previous == nil {
 		opt.SuggestedValuesFn = fn
+		return
+	}
+	opt.SuggestedValuesFn = func(target string, partial string) []string {
+		return append(previous(target, partial), fn(target, partial)...)
 	}
 }
 
@@ -204,11 +382,65 @@ func (gopt *GetOpt) Value(name string) interface{} {
 //	opt.SetValue("map", "hello=world", "hola=mundo") // map[string]string
 func (gopt *GetOpt) SetValue(name string, value ...string) error {
 	if v, ok := gopt.programTree.ChildOptions[name]; ok {
-		return v.Save(value...)
+		err := v.Save(value...)
+		if err != nil {
+			return optionError(err)
+		}
+		v.SetSource(option.SourceSetValue, "")
+		return nil
 	}
 	return ErrorNotFound
 }
 
+// SourceKind - Indicates where the value of an option came from.
+type SourceKind int
+
+// Value sources
+const (
+	SourceDefault  SourceKind = SourceKind(option.SourceDefault)  // The option wasn't set, the value is the default
+	SourceCLI      SourceKind = SourceKind(option.SourceCLI)      // Set on the command line
+	SourceEnv      SourceKind = SourceKind(option.SourceEnv)      // Set from an environment variable
+	SourceConfig   SourceKind = SourceKind(option.SourceConfig)   // Set from a configuration file
+	SourceSetValue SourceKind = SourceKind(option.SourceSetValue) // Set with opt.SetValue
+)
+
+func (k SourceKind) String() string {
+	switch k {
+	case SourceCLI:
+		return "cli"
+	case SourceEnv:
+		return "env"
+	case SourceConfig:
+		return "config"
+	case SourceSetValue:
+		return "set-value"
+	default:
+		return "default"
+	}
+}
+
+// ValueSource - Describes where the value of an option came from.
+//
+// Name holds the alias used on the command line, the name of the environment
+// variable or the path of the configuration file, depending on the Kind.
+type ValueSource struct {
+	Kind SourceKind
+	Name string
+}
+
+// Source - Returns where the value of the given option came from.
+// Useful for diagnostics that explain why an option has the value it has.
+//
+// If the `name` is an option that wasn't declared it will return a SourceDefault ValueSource.
+//
+// For options that can be called multiple times, the last source used is returned.
+func (gopt *GetOpt) Source(name string) ValueSource {
+	if v, ok := gopt.programTree.ChildOptions[name]; ok {
+		return ValueSource{Kind: SourceKind(v.SourceKind), Name: v.SourceName}
+	}
+	return ValueSource{Kind: SourceDefault}
+}
+
 // Bool - define a `bool` option and its aliases.
 // It returns a `*bool` pointing to the variable holding the result.
 // If the option is found, the result will be the opposite of the provided default.
@@ -227,6 +459,7 @@ func (gopt *GetOpt) BoolVar(p *bool, name string, def bool, fns ...ModifyFn) {
 	for _, fn := range fns {
 		fn(gopt, n)
 	}
+	gopt.bindEnvPrefix(n)
 }
 
 // String - define a `string` option and its aliases.
@@ -246,6 +479,7 @@ func (gopt *GetOpt) StringVar(p *string, name, def string, fns ...ModifyFn) {
 	for _, fn := range fns {
 		fn(gopt, n)
 	}
+	gopt.bindEnvPrefix(n)
 }
 
 // StringOptional - define a `string` option and its aliases.
@@ -271,6 +505,7 @@ func (gopt *GetOpt) StringVarOptional(p *string, name, def string, fns ...Modify
 	for _, fn := range fns {
 		fn(gopt, n)
 	}
+	gopt.bindEnvPrefix(n)
 }
 
 // StringSlice - define a `[]string` option and its aliases.
@@ -315,6 +550,7 @@ func (gopt *GetOpt) StringSliceVar(p *[]string, name string, min, max int, fns .
 	for _, fn := range fns {
 		fn(gopt, n)
 	}
+	gopt.bindEnvPrefix(n)
 	n.Synopsis()
 }
 
@@ -333,6 +569,7 @@ func (gopt *GetOpt) IntVar(p *int, name string, def int, fns ...ModifyFn) {
 	for _, fn := range fns {
 		fn(gopt, n)
 	}
+	gopt.bindEnvPrefix(n)
 }
 
 // IntOptional - define a `int` option and its aliases.
@@ -358,6 +595,7 @@ func (gopt *GetOpt) IntVarOptional(p *int, name string, def int, fns ...ModifyFn
 	for _, fn := range fns {
 		fn(gopt, n)
 	}
+	gopt.bindEnvPrefix(n)
 }
 
 // IntSlice - define a `[]int` option and its aliases.
@@ -412,6 +650,7 @@ func (gopt *GetOpt) IntSliceVar(p *[]int, name string, min, max int, fns ...Modi
 	for _, fn := range fns {
 		fn(gopt, n)
 	}
+	gopt.bindEnvPrefix(n)
 	n.Synopsis()
 }
 
@@ -429,6 +668,7 @@ func (gopt *GetOpt) IncrementVar(p *int, name string, def int, fns ...ModifyFn)
 	for _, fn := range fns {
 		fn(gopt, n)
 	}
+	gopt.bindEnvPrefix(n)
 }
 
 // Float64 - define an `float64` option and its aliases.
@@ -446,6 +686,7 @@ func (gopt *GetOpt) Float64Var(p *float64, name string, def float64, fns ...Modi
 	for _, fn := range fns {
 		fn(gopt, n)
 	}
+	gopt.bindEnvPrefix(n)
 }
 
 // Float64Optional - define an `float64` option and its aliases.
@@ -463,6 +704,7 @@ func (gopt *GetOpt) Float64VarOptional(p *float64, name string, def float64, fns
 	for _, fn := range fns {
 		fn(gopt, n)
 	}
+	gopt.bindEnvPrefix(n)
 }
 
 func (gopt *GetOpt) Float64Slice(name string, min, max int, fns ...ModifyFn) *[]float64 {
@@ -479,9 +721,123 @@ func (gopt *GetOpt) Float64SliceVar(p *[]float64, name string, min, max int, fns
 	for _, fn := range fns {
 		fn(gopt, n)
 	}
+	gopt.bindEnvPrefix(n)
 	n.Synopsis()
 }
 
+// Duration - define a `time.Duration` option and its aliases.
+//
+// The value is parsed with `time.ParseDuration`.
+// For example: `--timeout 1m30s`.
+func (gopt *GetOpt) Duration(name string, def time.Duration, fns ...ModifyFn) *time.Duration {
+	gopt.DurationVar(&def, name, def, fns...)
+	return &def
+}
+
+// DurationVar - define a `time.Duration` option and its aliases.
+// The result will be available through the variable marked by the given pointer.
+//
+// The value is parsed with `time.ParseDuration`.
+// For example: `--timeout 1m30s`.
+func (gopt *GetOpt) DurationVar(p *time.Duration, name string, def time.Duration, fns ...ModifyFn) {
+	*p = def
+	n := option.New(name, option.DurationType, p)
+	gopt.programTree.AddChildOption(name, n)
+	for _, fn := range fns {
+		fn(gopt, n)
+	}
+	gopt.bindEnvPrefix(n)
+}
+
+// DurationOptional - define a `time.Duration` option and its aliases.
+//
+// DurationOptional will set the duration to the provided default value when no value is given.
+// For example, when called with `--timeout 5s`, the value is `5s`.
+// when called with `--timeout` the value is the given default.
+func (gopt *GetOpt) DurationOptional(name string, def time.Duration, fns ...ModifyFn) *time.Duration {
+	gopt.DurationVarOptional(&def, name, def, fns...)
+	return &def
+}
+
+// DurationVarOptional - define a `time.Duration` option and its aliases.
+// The result will be available through the variable marked by the given pointer.
+//
+// DurationVarOptional will set the duration to the provided default value when no value is given.
+// For example, when called with `--timeout 5s`, the value is `5s`.
+// when called with `--timeout` the value is the given default.
+func (gopt *GetOpt) DurationVarOptional(p *time.Duration, name string, def time.Duration, fns ...ModifyFn) {
+	*p = def
+	n := option.New(name, option.DurationOptionalType, p)
+	gopt.programTree.AddChildOption(name, n)
+	for _, fn := range fns {
+		fn(gopt, n)
+	}
+	gopt.bindEnvPrefix(n)
+}
+
+// DurationSlice - define a `[]time.Duration` option and its aliases.
+//
+// DurationSlice will accept multiple calls to the same option and append them
+// to the `[]time.Duration`.
+// For example, when called with `--retry 1s --retry 5s`, the value is `[]time.Duration{1 * time.Second, 5 * time.Second}`.
+//
+// Additionally, DurationSlice will allow to define a min and max amount of
+// arguments to be passed at once.
+// For example, when min is 1 and max is 3 and called with `--retry 1s 5s 10s`,
+// the value is `[]time.Duration{1 * time.Second, 5 * time.Second, 10 * time.Second}`.
+func (gopt *GetOpt) DurationSlice(name string, min, max int, fns ...ModifyFn) *[]time.Duration {
+	s := []time.Duration{}
+	gopt.DurationSliceVar(&s, name, min, max, fns...)
+	return &s
+}
+
+// DurationSliceVar - define a `[]time.Duration` option and its aliases.
+//
+// DurationSliceVar will accept multiple calls to the same option and append them
+// to the `[]time.Duration`.
+// For example, when called with `--retry 1s --retry 5s`, the value is `[]time.Duration{1 * time.Second, 5 * time.Second}`.
+//
+// Additionally, DurationSliceVar will allow to define a min and max amount of
+// arguments to be passed at once.
+// For example, when min is 1 and max is 3 and called with `--retry 1s 5s 10s`,
+// the value is `[]time.Duration{1 * time.Second, 5 * time.Second, 10 * time.Second}`.
+func (gopt *GetOpt) DurationSliceVar(p *[]time.Duration, name string, min, max int, fns ...ModifyFn) {
+	n := option.New(name, option.DurationRepeatType, p)
+	n.MinArgs = min
+	n.MaxArgs = max
+	gopt.programTree.AddChildOption(name, n)
+	for _, fn := range fns {
+		fn(gopt, n)
+	}
+	gopt.bindEnvPrefix(n)
+	n.Synopsis()
+}
+
+// Value - Interface for user defined option types.
+// It follows the same contract as the standard library's flag.Value.
+//
+// Set is called with each argument passed to the option and String is used to
+// display the default value in the help output.
+type Value = option.Value
+
+// Var - define an option of a user defined type and its aliases.
+// The value must implement the `getoptions.Value` interface.
+//
+// The option requires an argument, for example: `--ip 127.0.0.1`.
+// Every time the option is passed the argument is given to `value.Set`.
+//
+// `opt.Value(name)` returns the given `value`.
+// If the value additionally implements the standard library's flag.Getter
+// interface, `opt.Value(name)` returns the result of `value.Get()`.
+func (gopt *GetOpt) Var(value Value, name string, fns ...ModifyFn) {
+	n := option.New(name, option.ValueType, value)
+	gopt.programTree.AddChildOption(name, n)
+	for _, fn := range fns {
+		fn(gopt, n)
+	}
+	gopt.bindEnvPrefix(n)
+}
+
 // StringMap - define a `map[string]string` option and its aliases.
 //
 // StringMap will accept multiple calls of `key=value` type to the same option
@@ -530,6 +886,7 @@ func (gopt *GetOpt) StringMapVar(m *map[string]string, name string, min, max int
 	for _, fn := range fns {
 		fn(gopt, n)
 	}
+	gopt.bindEnvPrefix(n)
 	n.Synopsis()
 }
 
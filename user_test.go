@@ -11,6 +11,7 @@ package getoptions
 import (
 	"bytes"
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -194,10 +195,14 @@ func TestCompletion(t *testing.T) {
 	}
 	called := false
 	exitFn = func(code int) { called = true }
+	defer func() { exitFn = os.Exit }()
 
 	cleanup := func() {
 		os.Setenv("COMP_LINE", "")
 		os.Setenv("ZSHELL", "")
+		os.Setenv("ZSH_DESCRIBE", "")
+		os.Setenv("FISHSHELL", "")
+		os.Setenv("POWERSHELL", "")
 		completionWriter = os.Stdout
 		Writer = os.Stderr
 		called = false
@@ -244,6 +249,14 @@ func TestCompletion(t *testing.T) {
 		{"zshell command", func() { os.Setenv("ZSHELL", "true"); os.Setenv("COMP_LINE", "./program show --level") }, []string{}, "--level=\n--level=<string>\n", ""},
 		{"zshell command", func() { os.Setenv("ZSHELL", "true"); os.Setenv("COMP_LINE", "./program show --level=") }, []string{}, "--level=debug\n--level=error\n--level=info\n", ""},
 		{"zshell command", func() { os.Setenv("ZSHELL", "true"); os.Setenv("COMP_LINE", "./program show --level=i") }, []string{}, "--level=infinity\n--level=info\n--level=informational\n", ""},
+
+		// fish
+		{"fish option", func() { os.Setenv("FISHSHELL", "true"); os.Setenv("COMP_LINE", "./program --fl") }, []string{}, "--flag\n--fleg\n", ""},
+		{"fish command", func() { os.Setenv("FISHSHELL", "true"); os.Setenv("COMP_LINE", "./program h") }, []string{}, "help\n", ""},
+		{"fish command", func() { os.Setenv("FISHSHELL", "true"); os.Setenv("COMP_LINE", "./program help ") }, []string{}, "log\nshow\n", ""},
+		{"fish command", func() { os.Setenv("FISHSHELL", "true"); os.Setenv("COMP_LINE", "./program --profile=p") }, []string{}, "--profile=production\n", ""},
+		{"fish command", func() { os.Setenv("FISHSHELL", "true"); os.Setenv("COMP_LINE", "./program show --level") }, []string{}, "--level=\n", ""},
+		{"fish command", func() { os.Setenv("FISHSHELL", "true"); os.Setenv("COMP_LINE", "./program show --level=i") }, []string{}, "--level=infinity\n--level=info\n--level=informational\n", ""},
 	}
 	validValuesTests := []struct {
 		name     string
@@ -364,4 +377,338 @@ ERROR: wrong value for option 'profile', valid values are ["dev" "staging" "prod
 			cleanup()
 		})
 	}
+	// Rows that need their own program definition and shell environment.
+	zsh := map[string]string{"ZSHELL": "true"}
+	zshDescribe := map[string]string{"ZSHELL": "true", "ZSH_DESCRIBE": "true"}
+	fish := map[string]string{"FISHSHELL": "true"}
+	powerShell := map[string]string{"POWERSHELL": "true"}
+
+	fishProgram := func() *GetOpt {
+		opt := New()
+		opt.Self("program", "")
+		opt.Bool("debug", false, opt.Description("Enable\ndebug output"))
+		opt.String("profile", "", opt.Description("Profile to use"))
+		opt.Bool("quiet", false)
+		opt.NewCommand("log", "Show logs")
+		opt.NewCommand("show", "")
+		opt.HelpCommand("help")
+		return opt
+	}
+	powerShellProgram := func() *GetOpt {
+		opt := New()
+		opt.Self("program", "")
+		opt.Bool("debug", false, opt.Description("Enable debug output"))
+		opt.String("profile", "", opt.SuggestedValues("dev", "prod"))
+		opt.NewCommand("log", "Show logs")
+		opt.HelpCommand("help")
+		return opt
+	}
+	describedProgram := func() *GetOpt {
+		opt := New()
+		opt.Self("program", "")
+		opt.Bool("verbose", false, opt.Description("increase verbosity"))
+		opt.String("profile", "")
+		opt.NewCommand("log", "Show logs").ArgCompletionsDescribedFns(func(target string, prev []string, s string) []Completion {
+			return []Completion{{"host:1", "first host"}, {"host2", ""}}
+		})
+		opt.HelpCommand("help")
+		return opt
+	}
+	completionCommandProgram := func() *GetOpt {
+		opt := New()
+		opt.Self("tool", "")
+		opt.Bool("debug", false)
+		opt.CompletionCommand("completion")
+		opt.HelpCommand("help")
+		return opt
+	}
+	fileProgram := func() *GetOpt {
+		opt := New()
+		opt.Self("program", "")
+		opt.String("config", "", opt.CompleteFiles("*.json"))
+		opt.String("out", "", opt.CompleteDirs())
+		stdin := func(target, partial string) []string { return []string{"-"} }
+		opt.String("input", "", opt.SuggestedValuesFn(stdin), opt.CompleteFiles("*.yaml"))
+		opt.String("output", "", opt.CompleteDirs(), opt.SuggestedValuesFn(stdin))
+		opt.NewCommand("show", "").ArgCompleteFiles()
+		opt.NewCommand("cd", "").ArgCompleteDirs()
+		return opt
+	}
+	instances := map[string][]string{
+		"us-east-1": {"i-east-a", "i-east-b"},
+		"us-west-2": {"i-west-a"},
+	}
+	contextProgram := func() *GetOpt {
+		opt := New()
+		opt.Self("program", "")
+		opt.String("region", "us-east-1")
+		opt.Bool("all", false)
+		opt.String("instance", "", opt.SuggestedValuesContextFn(func(target string, parsed ParsedOptions, partial string) []string {
+			return instances[parsed.Value("region").(string)]
+		}))
+		ssh := opt.NewCommand("ssh", "")
+		ssh.ArgCompletionsContextFns(func(target string, parsed ParsedOptions, previousArgs []string, partial string) []string {
+			if parsed.Called("all") {
+				return []string{"i-east-a", "i-east-b", "i-west-a"}
+			}
+			return []string{parsed.CalledAs("region") + ":" + parsed.Source("region").Kind.String()}
+		})
+		return opt
+	}
+	separateValueProgram := func() *GetOpt {
+		opt := New()
+		opt.Self("program", "")
+		opt.Bool("verbose", false)
+		opt.String("profile", "", opt.SuggestedValues("dev", "prod", "staging"))
+		opt.Int("port", 0, opt.SuggestedValuesFn(func(target string, partial string) []string {
+			return []string{"8080", "8443"}
+		}))
+		opt.StringOptional("color", "auto", opt.ValidValues("always", "never"))
+		opt.StringSlice("tag", 1, 2, opt.SuggestedValues("a", "b"))
+		opt.NewCommand("deploy", "")
+		return opt
+	}
+	values := func(list ...string) ArgCompletionsFn {
+		return func(target string, previousArgs []string, partial string) []string {
+			return list
+		}
+	}
+	namedArgProgram := func() *GetOpt {
+		opt := New()
+		opt.Self("program", "")
+		opt.NamedArg("<env>", "", values("dev", "prod"))
+		cp := opt.NewCommand("cp", "")
+		cp.NamedArg("<src>", "", values("src-a", "src-b"))
+		cp.NamedArg("<dst>", "", values("dst-a"))
+		return opt
+	}
+
+	programTests := []struct {
+		name     string
+		env      map[string]string
+		program  func() *GetOpt
+		compLine string
+		expected string
+	}{
+		// fish
+		{"fish options", fish, fishProgram, "program --", "--debug\tEnable debug output\n--help\n--profile=\tProfile to use\n--quiet\n"},
+		{"fish commands", fish, fishProgram, "program ", "help\nlog\tShow logs\nshow\n"},
+
+		// powershell
+		{"powershell options", powerShell, powerShellProgram, "program --", "--debug\tParameterName\tEnable debug output\n--help\tParameterName\t--help\n--profile=\tParameterName\t--profile=\n"},
+		{"powershell option values", powerShell, powerShellProgram, "program --profile=", "--profile=dev\tParameterName\t--profile=dev\n--profile=prod\tParameterName\t--profile=prod\n"},
+		{"powershell commands", powerShell, powerShellProgram, "program ", "help\tParameterValue\thelp\nlog\tParameterValue\tShow logs\n"},
+
+		// described completions
+		{"zsh describe options", zshDescribe, describedProgram, "program --", "--help\n--profile=\n--verbose:increase verbosity\n"},
+		{"zsh describe commands", zshDescribe, describedProgram, "program ", "help\nlog:Show logs\n"},
+		{"zsh describe args", zshDescribe, describedProgram, "program log ", "help\nhost2\nhost\\:1:first host\n"},
+		{"zsh described args", zsh, describedProgram, "program log ", "help\nhost2\nhost:1\n"},
+		{"bash described args", nil, describedProgram, "program log ", "help\nhost2\nhost:1\n"},
+		{"fish described args", fish, describedProgram, "program log ", "help\nhost2\nhost:1\tfirst host\n"},
+
+		// completion command
+		{"completion command shells", nil, completionCommandProgram, "tool completion ", "bash\nfish\nhelp\npowershell\nzsh\n"},
+
+		// files and dirs
+		{"option files", nil, fileProgram, "program --config=", "a.json\nc.json\ndir/\n"},
+		{"option files prefix", nil, fileProgram, "program --config=d", "dir/\ndir/ \n"},
+		{"option files in dir", nil, fileProgram, "program --config=dir/", "dir/x.json\n"},
+		{"option dirs", nil, fileProgram, "program --out=", "dir/\ndir/ \n"},
+		{"option dirs fish", fish, fileProgram, "program --out=", "--out=dir/\n"},
+		{"option files with suggested values fn", nil, fileProgram, "program --input=", "-\nb.yaml\ndir/\n"},
+		{"option dirs with suggested values fn", nil, fileProgram, "program --output=", "-\ndir/\n"},
+		{"option dirs with suggested values fn prefix", nil, fileProgram, "program --output=d", "dir/\ndir/ \n"},
+		{"arg files", nil, fileProgram, "program show ", "a.json\nb.yaml\nc.json\ndir/\n"},
+		{"arg files prefix", nil, fileProgram, "program show a", "a.json \n"},
+		{"arg dirs", nil, fileProgram, "program cd ", "dir/\ndir/ \n"},
+		{"arg dirs fish", fish, fileProgram, "program cd ", "dir/\n"},
+
+		// parsed options context
+		{"context option default", nil, contextProgram, "program --instance=", "i-east-a\ni-east-b\n"},
+		{"context option parsed", nil, contextProgram, "program --region us-west-2 --instance=", "i-west-a\n"},
+		{"context option parsed with equal", nil, contextProgram, "program --region=us-west-2 --instance=", "i-west-a\n"},
+		{"context arg called", nil, contextProgram, "program ssh --all ", "i-east-a\ni-east-b\ni-west-a\n"},
+		{"context arg source", nil, contextProgram, "program ssh --region us-west-2 ", "region:cli \n"},
+		{"context arg default", nil, contextProgram, "program ssh ", ":default \n"},
+
+		// option values as separate words
+		{"suggested values", nil, separateValueProgram, "program --profile ", "dev\nprod\nstaging\n"},
+		{"suggested values partial", nil, separateValueProgram, "program --profile p", "prod \n"},
+		{"suggested values fish", fish, separateValueProgram, "program --profile ", "dev\nprod\nstaging\n"},
+		{"suggested values fn", nil, separateValueProgram, "program --port 80", "8080 \n"},
+		{"after value", nil, separateValueProgram, "program --profile dev ", "deploy \n"},
+		{"after bool", nil, separateValueProgram, "program --verbose ", "deploy \n"},
+		{"optional value", nil, separateValueProgram, "program --color ", "always\ndeploy\nnever\n"},
+		{"slice min", nil, separateValueProgram, "program --tag ", "a\nb\n"},
+		{"slice max", nil, separateValueProgram, "program --tag a ", "a\nb\ndeploy\n"},
+		{"slice after max", nil, separateValueProgram, "program --tag a b ", "deploy \n"},
+
+		// named args
+		{"named arg root first", nil, namedArgProgram, "program ", "cp\ndev\nprod\n"},
+		{"named arg root first partial", nil, namedArgProgram, "program d", "dev\nprod\n"},
+		{"named arg root second", nil, namedArgProgram, "program dev ", "cp \n"},
+		{"named arg command first", nil, namedArgProgram, "program cp ", "src-a\nsrc-b\n"},
+		{"named arg command second", nil, namedArgProgram, "program cp src-a ", "dst-a \n"},
+		{"named arg command third", nil, namedArgProgram, "program cp src-a dst-a ", "\n"},
+	}
+
+	// File completion rows run from a directory with known contents.
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+	dir := t.TempDir()
+	for _, name := range []string{"a.json", "b.yaml", "c.json", "dir/x.json"} {
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755)
+		os.WriteFile(filepath.Join(dir, name), []byte{}, 0644)
+	}
+	os.Chdir(dir)
+
+	for _, tt := range programTests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+			os.Setenv("COMP_LINE", tt.compLine)
+			completionBuf := new(bytes.Buffer)
+			completionWriter = completionBuf
+			_, err := tt.program().Parse([]string{})
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if !called {
+				t.Errorf("COMP_LINE set and exit wasn't called")
+			}
+			if completionBuf.String() != tt.expected {
+				t.Errorf("diff:\n%s", firstDiff(completionBuf.String(), tt.expected))
+			}
+			cleanup()
+		})
+	}
+}
+
+func TestCompletionScripts(t *testing.T) {
+	opt := New()
+	opt.Self("program", "")
+
+	t.Run("fish", func(t *testing.T) {
+		expected := "complete -c program -f -a '(env COMP_LINE=(commandline -cp) FISHSHELL=true program)'\n"
+		if opt.FishCompletionScript() != expected {
+			t.Errorf("diff:\n%s", firstDiff(opt.FishCompletionScript(), expected))
+		}
+	})
+
+	t.Run("powershell", func(t *testing.T) {
+		script := opt.PowerShellCompletionScript()
+		for _, s := range []string{
+			"Register-ArgumentCompleter -Native -CommandName 'program' -ScriptBlock {",
+			"    $env:POWERSHELL = 'true'\n",
+			"    & 'program' | ForEach-Object {\n",
+			"        $text, $type, $toolTip = $_ -split \"`t\", 3\n",
+		} {
+			if !strings.Contains(script, s) {
+				t.Errorf("Missing %q in script:\n%s", s, script)
+			}
+		}
+	})
+
+	t.Run("zsh", func(t *testing.T) {
+		expected := `_program() {
+    local -a completions
+    local line="${(j: :)words[1,CURRENT]}"
+    completions=("${(@f)$(COMP_LINE="$line" ZSHELL=true ZSH_DESCRIBE=true program 2>/dev/null)}")
+    _describe 'values' completions
+}
+compdef _program program
+`
+		if opt.ZshCompletionScript() != expected {
+			t.Errorf("diff:\n%s", firstDiff(opt.ZshCompletionScript(), expected))
+		}
+	})
+}
+
+func TestCompletionCommand(t *testing.T) {
+	defer func() { completionWriter = os.Stdout }()
+	setup := func() *GetOpt {
+		opt := New()
+		opt.Self("tool", "")
+		opt.Bool("debug", false)
+		opt.CompletionCommand("completion")
+		opt.HelpCommand("help")
+		return opt
+	}
+
+	tests := []struct {
+		shell    string
+		expected string
+	}{
+		{"bash", "complete -o default -C tool tool\n"},
+		{"zsh", setup().ZshCompletionScript()},
+		{"fish", setup().FishCompletionScript()},
+		{"powershell", setup().PowerShellCompletionScript()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			completionWriter = buf
+			opt := setup()
+			remaining, err := opt.Parse([]string{"completion", tt.shell})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			err = opt.Dispatch(context.Background(), remaining)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("diff:\n%s", firstDiff(buf.String(), tt.expected))
+			}
+		})
+	}
+
+	t.Run("unknown shell", func(t *testing.T) {
+		completionWriter = new(bytes.Buffer)
+		opt := setup()
+		remaining, err := opt.Parse([]string{"completion", "tcsh"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		err = opt.Dispatch(context.Background(), remaining)
+		if err == nil || !errors.Is(err, ErrorParsing) {
+			t.Errorf("Expected parsing error: %v", err)
+		}
+	})
+
+}
+
+func TestClosestNames(t *testing.T) {
+	candidates := []string{"verbose", "v", "version", "profile", "log", "help"}
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{"verbos", []string{"verbose"}},
+		{"versoin", []string{"version"}},
+		{"vrebose", []string{"verbose"}},
+		{"proflie", []string{"profile"}},
+		{"lgo", []string{"log"}},
+		{"ver", []string{"verbose", "version"}},
+		{"x", []string{}},
+		{"v", []string{}},
+		{"help", []string{}},
+		{"unrelated", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := closestNames(tt.name, candidates)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+	if levenshtein("kitten", "sitting") != 3 {
+		t.Errorf("wrong distance: %d", levenshtein("kitten", "sitting"))
+	}
 }